@@ -0,0 +1,70 @@
+// Package gathuk
+package gathuk
+
+import (
+	"reflect"
+	"strings"
+)
+
+// commenter is implemented by codecs that preserve the comments of the
+// content they decoded, such as the built-in .env codec.
+type commenter interface {
+	Comments() map[string]string
+}
+
+// addComments records the comments of a decoded source, keyed by key as it
+// appears in the source. Comments of later sources replace earlier ones.
+func (g *Gathuk[T]) addComments(comments map[string]string) {
+	if len(comments) == 0 {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.comments == nil {
+		g.comments = make(map[string]string, len(comments))
+	}
+	for k, v := range comments {
+		g.comments[strings.ToUpper(k)] = v
+	}
+}
+
+// CommentFor returns the comment attached to a configuration key in the
+// loaded sources, or "" if the key has no comment.
+//
+// A key's comment is its inline comment or, when there is none, the block of
+// comment lines directly above it. Comments are currently preserved for .env
+// sources only.
+//
+// The path can be given as the Go field path ("Database.Host"), the dotted
+// config path ("db.host") or the .env key ("DB_HOST").
+//
+// Parameters:
+//   - path: The key to look up
+//
+// Returns the comment text without comment markers.
+//
+// Example:
+//
+//	// config.env:
+//	// # Primary database host
+//	// DB_HOST=localhost
+//	// DB_PORT=5432 # default postgres port
+//	gt.LoadConfigFiles("config.env")
+//
+//	gt.CommentFor("Database.Host") // Returns: "Primary database host"
+//	gt.CommentFor("db.port")       // Returns: "default postgres port"
+func (g *Gathuk[T]) CommentFor(path string) string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if len(g.comments) == 0 {
+		return ""
+	}
+
+	for _, f := range typeFields(reflect.TypeOf(&g.value).Elem()) {
+		if f.Path == path || f.JSONKey == path {
+			return g.comments[f.EnvKey]
+		}
+	}
+	return g.comments[strings.ToUpper(strings.ReplaceAll(path, ".", "_"))]
+}
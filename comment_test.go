@@ -0,0 +1,31 @@
+// Package gathuk
+package gathuk
+
+import (
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+func TestCommentFor(t *testing.T) {
+	t.Run("Test 1: block and inline comments", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		err := gt.LoadConfigFiles("./example/dotenv/.example_commented.env")
+		customtests.OK(t, err)
+		customtests.Equals(t, "hello", gt.GetConfig().SimpleC)
+
+		customtests.Equals(t, "Simple comment\nspanning two lines", gt.CommentFor("SimpleC"))
+		customtests.Equals(t, "inline comment", gt.CommentFor("simple_e"))
+		customtests.Equals(t, "inline comment", gt.CommentFor("SIMPLE_E"))
+	})
+
+	t.Run("Test 2: missing comments", func(t *testing.T) {
+		gt := NewGathuk[Simple2]()
+
+		err := gt.LoadConfigFiles(EXAMPLE_ENV_FILE)
+		customtests.OK(t, err)
+		customtests.Equals(t, "", gt.CommentFor("Database.Host"))
+		customtests.Equals(t, "", gt.CommentFor("unknown"))
+	})
+}
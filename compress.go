@@ -0,0 +1,75 @@
+// Package gathuk
+package gathuk
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gzipExt is the file name suffix of gzip compressed configuration files.
+const gzipExt = ".gz"
+
+// fileFormat returns the format implied by the extension of filename, and
+// whether the file is gzip compressed. For a compressed file the format comes
+// from the extension before ".gz", e.g. "json" for "config.json.gz".
+func fileFormat(filename string) (format string, gzipped bool) {
+	if strings.EqualFold(filepath.Ext(filename), gzipExt) {
+		filename = filename[:len(filename)-len(gzipExt)]
+		gzipped = true
+	}
+	return strings.Trim(filepath.Ext(filename), "."), gzipped
+}
+
+// openFile opens a configuration file for reading, transparently
+// decompressing it when its name ends in ".gz".
+//
+// Parameters:
+//   - filename: Path to the configuration file
+//
+// Returns a reader of the decompressed content, to be closed by the caller,
+// or the error of os.Open or gzip.NewReader.
+func openFile(filename string) (io.ReadCloser, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, gzipped := fileFormat(filename); !gzipped {
+		return f, nil
+	}
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &gzipFile{Reader: zr, f: f}, nil
+}
+
+// gzipFile reads a gzip compressed file, closing both the gzip reader and
+// the file on Close.
+type gzipFile struct {
+	*gzip.Reader
+	f *os.File
+}
+
+func (g *gzipFile) Close() error {
+	err := g.Reader.Close()
+	if ferr := g.f.Close(); err == nil {
+		err = ferr
+	}
+	return err
+}
+
+// readFile reads a whole configuration file like os.ReadFile, decompressing
+// it when its name ends in ".gz".
+func readFile(filename string) ([]byte, error) {
+	f, err := openFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
@@ -0,0 +1,62 @@
+// Package gathuk
+package gathuk
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+func TestGathukGzipFiles(t *testing.T) {
+	t.Run("Test 1: load a gzipped json file", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, err := zw.Write([]byte(`{"simple_c": "zipped", "simple_e": 3}`))
+		customtests.OK(t, err)
+		customtests.OK(t, zw.Close())
+
+		path := filepath.Join(t.TempDir(), "config.json.gz")
+		customtests.OK(t, os.WriteFile(path, buf.Bytes(), 0o644))
+
+		gt := NewGathuk[Simple]()
+		customtests.OK(t, gt.LoadConfigFiles(path))
+		customtests.Equals(t, Simple{SimpleC: "zipped", SimpleE: 3}, gt.GetConfig())
+	})
+
+	t.Run("Test 2: write and read back a gzipped env file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.env.gz")
+
+		gt := NewGathuk[Simple]()
+		customtests.OK(t, gt.WriteConfigFile(path, 0, Simple{SimpleC: "zipped", SimpleE: 3}))
+
+		data, err := os.ReadFile(path)
+		customtests.OK(t, err)
+		customtests.Assert(t, bytes.HasPrefix(data, []byte{0x1f, 0x8b}), "file is not gzip compressed: %q", data)
+
+		customtests.OK(t, gt.LoadConfigFiles(path))
+		customtests.Equals(t, Simple{SimpleC: "zipped", SimpleE: 3}, gt.GetConfig())
+	})
+
+	t.Run("Test 3: invalid gzip data", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json.gz")
+		customtests.OK(t, os.WriteFile(path, []byte(`{"simple_c": "plain"}`), 0o644))
+
+		gt := NewGathuk[Simple]()
+		err := gt.LoadConfigFiles(path)
+		customtests.Assert(t, err != nil, "expected gzip error")
+	})
+
+	t.Run("Test 4: format comes from the inner extension", func(t *testing.T) {
+		format, gzipped := fileFormat("conf.d/app.JSON.GZ")
+		customtests.Equals(t, "JSON", format)
+		customtests.Equals(t, true, gzipped)
+
+		format, gzipped = fileFormat("config.env")
+		customtests.Equals(t, "env", format)
+		customtests.Equals(t, false, gzipped)
+	})
+}
@@ -2,7 +2,8 @@
 package gathuk
 
 import (
-	"errors"
+	"fmt"
+	"slices"
 	"strings"
 	"sync"
 
@@ -46,11 +47,22 @@ import (
 //	gt := gathuk.NewGathuk[Config]()
 //	gt.SetCustomCodecRegistry(registry)
 type DefaultCodecRegistry[T any] struct {
-	codecs map[string]option.Codec[T]
+	codecs  map[string]option.Codec[T]
+	options map[string]codecOptions
 
 	mu sync.Mutex
 }
 
+// builtinFormats lists the formats every DefaultCodecRegistry supports.
+var builtinFormats = []string{"env", "json"}
+
+// codecOptions holds the options bundled with a codec by
+// RegisterCodecWithOptions.
+type codecOptions struct {
+	decode *option.DecodeOption
+	encode *option.EncodeOption
+}
+
 // NewDefaultCodecRegister creates and initializes a new DefaultCodecRegistry.
 //
 // The returned registry is ready to use and includes built-in support for
@@ -125,6 +137,40 @@ func (dcr *DefaultCodecRegistry[T]) RegisterCodec(format string, codec option.Co
 	format = strings.ToLower(format)
 
 	dcr.codecs[format] = codec
+	delete(dcr.options, format)
+}
+
+// RegisterCodecWithOptions registers a codec for a specific file format
+// together with the decode and encode options it should use, so the codec is
+// pre-configured without separate SetDecodeOption/SetEncodeOption calls.
+//
+// The options are applied when the codec is retrieved through Encoder or
+// Decoder, unless options have been applied to the codec since. Either option
+// may be nil to leave it unset. Otherwise it behaves like RegisterCodec.
+//
+// Parameters:
+//   - format: The format name (e.g., "json", "yaml", "toml"). Case-insensitive
+//   - codec: The codec implementation for this format
+//   - decodeOpt: The decode options for the codec, or nil
+//   - encodeOpt: The encode options for the codec, or nil
+//
+// Example:
+//
+//	registry := gathuk.NewDefaultCodecRegister[Config]()
+//	registry.RegisterCodecWithOptions("json", &JSONCodec[Config]{},
+//	    &option.DecodeOption{CaseInsensitiveKeys: true}, nil)
+func (dcr *DefaultCodecRegistry[T]) RegisterCodecWithOptions(
+	format string, codec option.Codec[T], decodeOpt *option.DecodeOption, encodeOpt *option.EncodeOption,
+) {
+	dcr.RegisterCodec(format, codec)
+
+	dcr.mu.Lock()
+	defer dcr.mu.Unlock()
+
+	if dcr.options == nil {
+		dcr.options = make(map[string]codecOptions)
+	}
+	dcr.options[strings.ToLower(format)] = codecOptions{decode: decodeOpt, encode: encodeOpt}
 }
 
 // Encoder returns an encoder for the specified format.
@@ -161,7 +207,7 @@ func (dcr *DefaultCodecRegistry[T]) Encoder(format string) (option.Encoder[T], e
 	if v, ok := dcr.codec(format); ok {
 		return v, nil
 	}
-	return nil, errors.New("encoder not found for this format")
+	return nil, fmt.Errorf("encoder not found for format %q: %w", format, ErrFormatNotSupported)
 }
 
 // Decoder returns a decoder for the specified format.
@@ -199,13 +245,54 @@ func (dcr *DefaultCodecRegistry[T]) Decoder(format string) (option.Decoder[T], e
 	if v, ok := dcr.codec(format); ok {
 		return v, nil
 	}
-	return nil, errors.New("decoder not found for this format")
+	return nil, fmt.Errorf("decoder not found for format %q: %w", format, ErrFormatNotSupported)
+}
+
+// Formats returns the formats the registry has a codec for: the built-in
+// formats first, followed by the other registered formats in lexical order.
+//
+// This method is thread-safe.
+//
+// Example:
+//
+//	registry.RegisterCodec("yaml", &YAMLCodec[Config]{})
+//	registry.Formats() // Returns: ["env", "json", "yaml"]
+func (dcr *DefaultCodecRegistry[T]) Formats() []string {
+	dcr.mu.Lock()
+	defer dcr.mu.Unlock()
+
+	var registered []string
+	for format := range dcr.codecs {
+		if !slices.Contains(builtinFormats, format) {
+			registered = append(registered, format)
+		}
+	}
+	slices.Sort(registered)
+	return append(slices.Clone(builtinFormats), registered...)
+}
+
+// resetCodecs clears the internal state of every registered codec that
+// supports it (i.e. implements a Reset method).
+//
+// This method is thread-safe.
+func (dcr *DefaultCodecRegistry[T]) resetCodecs() {
+	dcr.mu.Lock()
+	defer dcr.mu.Unlock()
+
+	for _, c := range dcr.codecs {
+		if r, ok := c.(interface{ Reset() }); ok {
+			r.Reset()
+		}
+	}
 }
 
 // codec is an internal method that retrieves a codec for the specified format.
 //
 // This method first checks the registered codecs map. If no codec is found,
-// it checks for built-in codecs (currently only "env" format is built-in).
+// it checks for built-in codecs ("env" and "json"). A built-in codec is
+// created on first use and kept in the map, so options applied to it through
+// SetDecodeOption or SetEncodeOption are not lost between calls. Options
+// bundled by RegisterCodecWithOptions are applied to a codec that has none.
 //
 // Format names are case-insensitive.
 //
@@ -220,17 +307,35 @@ func (dcr *DefaultCodecRegistry[T]) Decoder(format string) (option.Decoder[T], e
 //   - option.Codec[T]: The codec implementation if found
 //   - bool: true if a codec was found, false otherwise
 func (dcr *DefaultCodecRegistry[T]) codec(format string) (option.Codec[T], bool) {
+	dcr.mu.Lock()
+	defer dcr.mu.Unlock()
+
 	format = strings.ToLower(format)
 	if v, ok := dcr.codecs[format]; ok {
+		if opts, ok := dcr.options[format]; ok {
+			if opts.decode != nil && !v.CheckDecodeOption() {
+				v.ApplyDecodeOption(opts.decode)
+			}
+			if opts.encode != nil && !v.CheckEncodeOption() {
+				v.ApplyEncodeOption(opts.encode)
+			}
+		}
 		return v, true
 	}
 
+	var v option.Codec[T]
 	switch format {
 	case "env":
-		return &dotenv.Codec[T]{}, true
+		v = &dotenv.Codec[T]{}
 	case "json":
-		return &json.Codec[T]{}, true
+		v = &json.Codec[T]{}
 	default:
 		return nil, false
 	}
+
+	if dcr.codecs == nil {
+		dcr.codecs = make(map[string]option.Codec[T])
+	}
+	dcr.codecs[format] = v
+	return v, true
 }
@@ -0,0 +1,74 @@
+// Package gathuk
+package gathuk
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/ahyalfan/gathuk/internal/encoding/dotenv"
+	"github.com/ahyalfan/gathuk/option"
+)
+
+// defaultFields returns the leaf fields of T carrying a `default` tag.
+func (g *Gathuk[T]) defaultFields() []fieldInfo {
+	var fields []fieldInfo
+	for _, f := range typeFields(reflect.TypeOf(&g.value).Elem()) {
+		if _, ok := f.Field.Tag.Lookup("default"); ok && f.EnvKey != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// defaultValue returns a value of T with every field carrying a `default`
+// tag set to the tag value, e.g. `default:"true"` or `default:"8080"`.
+//
+// Tag values are converted like .env values, so every field type the .env
+// codec supports can have a default. Invalid defaults are logged and leave
+// the field at its zero value.
+func (g *Gathuk[T]) defaultValue() T {
+	var val T
+
+	fields := g.defaultFields()
+	if len(fields) == 0 {
+		return val
+	}
+
+	var build []byte
+	for _, f := range fields {
+		build = append(build, strings.ToUpper(f.EnvKey)...)
+		build = append(build, '=')
+		build = append(build, dotenv.QuoteValue(f.Field.Tag.Get("default"))...)
+		build = append(build, '\n')
+	}
+
+	cdc := &dotenv.Codec[T]{}
+	cdc.ApplyDecodeOption(&option.DecodeOption{})
+	if err := cdc.Decode(build, &val); err != nil {
+		g.logger.Error("apply default tags", "error", err)
+	}
+	return val
+}
+
+// keepDecodedDefaults copies the fields carrying a `default` tag from src to
+// dst, including zero values.
+//
+// src is decoded on top of a copy of dst, so such a field of src differs from
+// dst only when the source sets its key. Copying it keeps a value explicitly
+// set by the source, e.g. DEBUG=false for `default:"true"`, which the zero
+// value preserving merge would otherwise drop.
+func (g *Gathuk[T]) keepDecodedDefaults(dst, src *T) {
+	dv := reflect.ValueOf(dst).Elem()
+	sv := reflect.ValueOf(src).Elem()
+	for _, f := range g.defaultFields() {
+		s, err := sv.FieldByIndexErr(f.Index)
+		if err != nil {
+			continue
+		}
+		d, err := dv.FieldByIndexErr(f.Index)
+		if err != nil || !d.CanSet() {
+			continue
+		}
+		d.Set(s)
+	}
+}
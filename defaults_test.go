@@ -0,0 +1,49 @@
+// Package gathuk
+package gathuk
+
+import (
+	"strings"
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+type DefaultsConfig struct {
+	Cache bool   `config:"cache" default:"true"`
+	Port  int    `config:"port" default:"8080"`
+	Host  string `config:"host"`
+}
+
+func TestGathukDefaults(t *testing.T) {
+	t.Run("Test 1: defaults apply when keys are absent", func(t *testing.T) {
+		gt := NewGathuk[DefaultsConfig]()
+		err := gt.LoadConfig(strings.NewReader(`{"host": "localhost"}`), "json")
+
+		customtests.OK(t, err)
+		customtests.Equals(t, DefaultsConfig{Cache: true, Port: 8080, Host: "localhost"}, gt.GetConfig())
+	})
+
+	t.Run("Test 2: default-true bool set to false by the file", func(t *testing.T) {
+		gt := NewGathuk[DefaultsConfig]()
+		err := gt.LoadConfig(strings.NewReader("CACHE=false\nHOST=localhost"), "env")
+
+		customtests.OK(t, err)
+		customtests.Equals(t, DefaultsConfig{Cache: false, Port: 8080, Host: "localhost"}, gt.GetConfig())
+	})
+
+	t.Run("Test 3: later file without the key keeps the earlier value", func(t *testing.T) {
+		gt := NewGathuk[DefaultsConfig]()
+		customtests.OK(t, gt.LoadConfig(strings.NewReader(`{"cache": false, "port": 0}`), "json"))
+		customtests.OK(t, gt.LoadConfig(strings.NewReader(`{"host": "localhost"}`), "json"))
+
+		customtests.Equals(t, DefaultsConfig{Cache: false, Port: 0, Host: "localhost"}, gt.GetConfig())
+	})
+
+	t.Run("Test 4: Reset restores defaults", func(t *testing.T) {
+		gt := NewGathuk[DefaultsConfig]()
+		customtests.OK(t, gt.LoadConfig(strings.NewReader(`{"cache": false}`), "json"))
+		gt.Reset()
+
+		customtests.Equals(t, DefaultsConfig{Cache: true, Port: 8080}, gt.GetConfig())
+	})
+}
@@ -0,0 +1,68 @@
+// Package gathuk
+package gathuk
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// FieldDoc documents a single configuration key of T.
+type FieldDoc struct {
+	Path     string // Go field path, e.g. "Database.Host"
+	EnvKey   string // Key in .env files, e.g. "DATABASE_HOST"
+	JSONKey  string // Dotted key path in JSON files, e.g. "database.host"
+	Type     string // Go type of the field, e.g. "int"
+	Default  string // Value of the `default` tag, if any
+	Required bool   // Whether the field is tagged `required:"true"`
+	Doc      string // Value of the `doc` tag, if any
+}
+
+// Describe returns documentation for every configuration key of T, assembled
+// from struct tags.
+//
+// Nested structs are flattened, so each entry describes a leaf field with its
+// full path and resolved keys. Entries are returned in field declaration order.
+// This is useful for generating `--help` output or configuration docs.
+//
+// Supported tags:
+//   - `doc:"..."`: Human readable description of the field
+//   - `default:"..."`: Default value shown in the docs
+//   - `required:"true"`: Marks the field as required
+//
+// Returns nil if T is not a struct type.
+//
+// Example:
+//
+//	type Config struct {
+//	    Port     int `config:"port" doc:"HTTP listen port" default:"8080"`
+//	    Database struct {
+//	        Host string `doc:"Database host" required:"true"`
+//	    } `config:"db"`
+//	}
+//
+//	for _, d := range gathuk.NewGathuk[Config]().Describe() {
+//	    fmt.Printf("%s (%s): %s\n", d.EnvKey, d.Type, d.Doc)
+//	}
+//	// PORT (int): HTTP listen port
+//	// DB_HOST (string): Database host
+func (g *Gathuk[T]) Describe() []FieldDoc {
+	fields := typeFields(reflect.TypeOf(&g.value).Elem())
+	if fields == nil {
+		return nil
+	}
+
+	docs := make([]FieldDoc, 0, len(fields))
+	for _, f := range fields {
+		required, _ := strconv.ParseBool(f.Field.Tag.Get("required"))
+		docs = append(docs, FieldDoc{
+			Path:     f.Path,
+			EnvKey:   f.EnvKey,
+			JSONKey:  f.JSONKey,
+			Type:     f.Field.Type.String(),
+			Default:  f.Field.Tag.Get("default"),
+			Required: required,
+			Doc:      f.Field.Tag.Get("doc"),
+		})
+	}
+	return docs
+}
@@ -0,0 +1,38 @@
+// Package gathuk
+package gathuk
+
+import (
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+type DocumentedDatabase struct {
+	Host string `doc:"Database host" required:"true"`
+	Port int    `config:"port" doc:"Database port" default:"5432"`
+}
+
+type DocumentedConfig struct {
+	Name     string             `doc:"Service name"`
+	Database DocumentedDatabase `config:"db"`
+	Internal string             `config:"-"`
+}
+
+func TestDescribe(t *testing.T) {
+	t.Run("Test 1: nested field docs", func(t *testing.T) {
+		gt := NewGathuk[DocumentedConfig]()
+
+		docs := gt.Describe()
+		customtests.Equals(t, []FieldDoc{
+			{Path: "Name", EnvKey: "NAME", JSONKey: "name", Type: "string", Doc: "Service name"},
+			{Path: "Database.Host", EnvKey: "DB_HOST", JSONKey: "db.host", Type: "string", Required: true, Doc: "Database host"},
+			{Path: "Database.Port", EnvKey: "DB_PORT", JSONKey: "db.port", Type: "int", Default: "5432", Doc: "Database port"},
+		}, docs)
+	})
+
+	t.Run("Test 2: non-struct config", func(t *testing.T) {
+		gt := NewGathuk[map[string]any]()
+
+		customtests.Equals(t, 0, len(gt.Describe()))
+	})
+}
@@ -0,0 +1,116 @@
+// Package gathuk
+package gathuk
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/ahyalfan/gathuk/shared"
+)
+
+// FieldDiff describes a value that differs between two configurations.
+type FieldDiff struct {
+	Path string // Go field path, e.g. "Database.Host", "Servers[1].Port" or "Labels[env]"
+	Old  any    // The value in the first configuration, nil if absent
+	New  any    // The value in the second configuration, nil if absent
+}
+
+// Diff compares two configurations and returns the values that differ, e.g.
+// to log what a reload actually changed.
+//
+// Structs are walked field by field like merging does, skipping unexported
+// fields. Slices are compared element by element and maps key by key, so a
+// change deep inside them is reported at its own path; elements or keys
+// present on one side only are reported with nil on the other side. Pointers
+// are followed, and all other values are compared with reflect.DeepEqual.
+//
+// Parameters:
+//   - a: The old configuration
+//   - b: The new configuration
+//
+// Returns the differences in field order, or nil if a and b are equal.
+//
+// Example:
+//
+//	old := gt.GetConfig()
+//	_ = gt.LoadConfigFiles("config.env")
+//	for _, d := range gathuk.Diff(old, gt.GetConfig()) {
+//	    log.Printf("%s: %v -> %v", d.Path, d.Old, d.New)
+//	}
+func Diff[T any](a, b T) []FieldDiff {
+	var diffs []FieldDiff
+	diffValues("", reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem(), &diffs)
+	return diffs
+}
+
+// diffValues appends the differences between a and b, found at path, to
+// diffs. Invalid values stand for elements or keys missing on one side.
+func diffValues(path string, a, b reflect.Value, diffs *[]FieldDiff) {
+	if !a.IsValid() || !b.IsValid() || a.Type() != b.Type() {
+		*diffs = append(*diffs, FieldDiff{Path: path, Old: valueOf(a), New: valueOf(b)})
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Struct:
+		if shared.IsScalarStruct(a.Type()) {
+			break
+		}
+		t := a.Type()
+		for i := 0; i < a.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			diffValues(joinKey(path, t.Field(i).Name, "."), a.Field(i), b.Field(i), diffs)
+		}
+		return
+
+	case reflect.Ptr, reflect.Interface:
+		if !a.IsNil() && !b.IsNil() {
+			diffValues(path, a.Elem(), b.Elem(), diffs)
+			return
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < max(a.Len(), b.Len()); i++ {
+			var ae, be reflect.Value
+			if i < a.Len() {
+				ae = a.Index(i)
+			}
+			if i < b.Len() {
+				be = b.Index(i)
+			}
+			diffValues(fmt.Sprintf("%s[%d]", path, i), ae, be, diffs)
+		}
+		return
+
+	case reflect.Map:
+		keys := a.MapKeys()
+		for _, key := range b.MapKeys() {
+			if !a.MapIndex(key).IsValid() {
+				keys = append(keys, key)
+			}
+		}
+		slices.SortFunc(keys, func(x, y reflect.Value) int {
+			return cmp.Compare(fmt.Sprint(x.Interface()), fmt.Sprint(y.Interface()))
+		})
+		for _, key := range keys {
+			diffValues(fmt.Sprintf("%s[%v]", path, key.Interface()), a.MapIndex(key), b.MapIndex(key), diffs)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+		*diffs = append(*diffs, FieldDiff{Path: path, Old: a.Interface(), New: b.Interface()})
+	}
+}
+
+// valueOf returns the value held by v, or nil if v is invalid.
+func valueOf(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
@@ -0,0 +1,65 @@
+// Package gathuk
+package gathuk
+
+import (
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+type DiffConfig struct {
+	Name     string
+	Database Database
+	Servers  []Item
+	Labels   map[string]string
+	Timeout  *int
+}
+
+func TestDiff(t *testing.T) {
+	t.Run("Test 1: identical configs", func(t *testing.T) {
+		timeout := 5
+		a := DiffConfig{Name: "app", Servers: []Item{{ID: "1"}}, Labels: map[string]string{"env": "prod"}, Timeout: &timeout}
+		b := DiffConfig{Name: "app", Servers: []Item{{ID: "1"}}, Labels: map[string]string{"env": "prod"}, Timeout: new(int)}
+		*b.Timeout = 5
+
+		customtests.Equals(t, 0, len(Diff(a, b)))
+		customtests.Equals(t, 0, len(Diff(a, a)))
+	})
+
+	t.Run("Test 2: nested, slice and map fields", func(t *testing.T) {
+		a := DiffConfig{
+			Name:     "app",
+			Database: Database{User: "admin", Server: "5432"},
+			Servers:  []Item{{ID: "1", Name: "api"}},
+			Labels:   map[string]string{"env": "prod", "team": "core"},
+		}
+		b := DiffConfig{
+			Name:     "app",
+			Database: Database{User: "admin", Server: "6543"},
+			Servers:  []Item{{ID: "1", Name: "worker"}, {ID: "2"}},
+			Labels:   map[string]string{"env": "dev", "tier": "1"},
+		}
+
+		customtests.Equals(t, []FieldDiff{
+			{Path: "Database.Server", Old: "5432", New: "6543"},
+			{Path: "Servers[0].Name", Old: "api", New: "worker"},
+			{Path: "Servers[1]", Old: nil, New: Item{ID: "2"}},
+			{Path: "Labels[env]", Old: "prod", New: "dev"},
+			{Path: "Labels[team]", Old: "core", New: nil},
+			{Path: "Labels[tier]", Old: nil, New: "1"},
+		}, Diff(a, b))
+	})
+
+	t.Run("Test 3: nil and set pointer", func(t *testing.T) {
+		timeout := 5
+		diffs := Diff(DiffConfig{}, DiffConfig{Timeout: &timeout})
+
+		customtests.Equals(t, 1, len(diffs))
+		customtests.Equals(t, "Timeout", diffs[0].Path)
+	})
+
+	t.Run("Test 4: non-struct configs", func(t *testing.T) {
+		diffs := Diff(map[string]any{"port": 80}, map[string]any{"port": 8080})
+		customtests.Equals(t, []FieldDiff{{Path: "[port]", Old: 80, New: 8080}}, diffs)
+	})
+}
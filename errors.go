@@ -0,0 +1,28 @@
+// Package gathuk
+package gathuk
+
+import "github.com/ahyalfan/gathuk/shared"
+
+// Error categories returned by Gathuk and the built-in codecs. Test for them
+// with errors.Is; the error message carries the details, such as the file,
+// format or field involved.
+//
+// Example:
+//
+//	err := gt.LoadConfigFiles("config.yaml")
+//	if errors.Is(err, gathuk.ErrFormatNotSupported) {
+//	    // register a codec for the format
+//	}
+var (
+	// ErrFormatNotSupported reports that no codec handles a format.
+	ErrFormatNotSupported = shared.ErrFormatNotSupported
+	// ErrTypeConversion reports that a value cannot be converted to the type
+	// of its field, e.g. PORT=abc for an int field.
+	ErrTypeConversion = shared.ErrTypeConversion
+	// ErrRequiredMissing reports that a field tagged `required:"true"` has no
+	// value after loading.
+	ErrRequiredMissing = shared.ErrRequiredMissing
+	// ErrFileNotFound reports that a configuration file does not exist. It
+	// also matches fs.ErrNotExist.
+	ErrFileNotFound = shared.ErrFileNotFound
+)
@@ -0,0 +1,61 @@
+// Package gathuk
+package gathuk
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+type CategoryConfig struct {
+	Name string `config:"name" required:"true"`
+	Port int    `config:"port"`
+}
+
+func TestErrorCategories(t *testing.T) {
+	t.Run("Test 1: missing file", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		err := gt.LoadConfigFiles(filepath.Join(t.TempDir(), "missing.env"))
+		customtests.Assert(t, errors.Is(err, ErrFileNotFound), "expected ErrFileNotFound, got %v", err)
+		customtests.Assert(t, errors.Is(err, fs.ErrNotExist), "expected fs.ErrNotExist, got %v", err)
+	})
+
+	t.Run("Test 2: unsupported format", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		err := gt.LoadConfig(strings.NewReader("a: b"), "weird")
+		customtests.Assert(t, errors.Is(err, ErrFormatNotSupported), "expected ErrFormatNotSupported, got %v", err)
+
+		path := filepath.Join(t.TempDir(), "config.weird")
+		customtests.OK(t, os.WriteFile(path, []byte("a: b"), 0o644))
+		err = gt.LoadConfigFiles(path)
+		customtests.Assert(t, errors.Is(err, ErrFormatNotSupported), "expected ErrFormatNotSupported, got %v", err)
+	})
+
+	t.Run("Test 3: type conversion", func(t *testing.T) {
+		gt := NewGathuk[CategoryConfig]()
+
+		err := gt.LoadConfig(strings.NewReader("NAME=api\nPORT=abc"), "env")
+		customtests.Assert(t, errors.Is(err, ErrTypeConversion), "expected ErrTypeConversion, got %v", err)
+
+		err = gt.LoadConfig(strings.NewReader(`{"name": "api", "port": "abc"}`), "json")
+		customtests.Assert(t, errors.Is(err, ErrTypeConversion), "expected ErrTypeConversion, got %v", err)
+	})
+
+	t.Run("Test 4: required value missing", func(t *testing.T) {
+		gt := NewGathuk[CategoryConfig]()
+
+		err := gt.LoadConfig(strings.NewReader("PORT=8080"), "env")
+		customtests.Assert(t, errors.Is(err, ErrRequiredMissing), "expected ErrRequiredMissing, got %v", err)
+		customtests.Assert(t, strings.Contains(err.Error(), "Name"), "error does not name the field: %v", err)
+		customtests.Equals(t, CategoryConfig{}, gt.GetConfig())
+
+		customtests.OK(t, gt.LoadConfig(strings.NewReader("NAME=api\nPORT=8080"), "env"))
+	})
+}
@@ -0,0 +1,53 @@
+// Package gathuk
+package gathuk
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/ahyalfan/gathuk/internal/encoding/dotenv"
+	"github.com/ahyalfan/gathuk/internal/encoding/json"
+)
+
+// FieldKey returns the configuration key a struct field maps to in the given
+// format, applying the same tag lookup, fallback and naming rules as the
+// built-in codecs.
+//
+// This allows tooling built on top of gathuk (documentation generators,
+// linters, flag binders) to stay in sync with the library's key mapping.
+//
+// Rules per format:
+//   - "env": the deprecated `nested` tag for struct fields, then `env` tag,
+//     then `config` tag, then `json` tag, falling back to the field name; the
+//     key is in UPPER_SNAKE_CASE
+//   - "json": `config` tag, then `json` tag, falling back to the field name in
+//     lower_snake_case
+//
+// The returned key does not include any nested prefix of parent structs.
+//
+// Parameters:
+//   - field: The struct field to resolve
+//   - format: The format name (e.g., "env", "json"). Case-insensitive
+//
+// Returns the key, or "" if the field is excluded (`config:"-"`) or the format
+// is not a built-in format.
+//
+// Example:
+//
+//	type Config struct {
+//	    MaxConn int `config:"max_connections"`
+//	}
+//
+//	field, _ := reflect.TypeOf(Config{}).FieldByName("MaxConn")
+//	gathuk.FieldKey(field, "env")  // Returns: "MAX_CONNECTIONS"
+//	gathuk.FieldKey(field, "json") // Returns: "max_connections"
+func FieldKey(field reflect.StructField, format string) string {
+	switch strings.ToLower(format) {
+	case "env":
+		return dotenv.FieldKey(field)
+	case "json":
+		return json.FieldKey(field)
+	default:
+		return ""
+	}
+}
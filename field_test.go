@@ -0,0 +1,61 @@
+// Package gathuk
+package gathuk
+
+import (
+	"reflect"
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+func TestFieldKey(t *testing.T) {
+	typ := reflect.TypeOf(User{})
+	field := func(name string) reflect.StructField {
+		f, ok := typ.FieldByName(name)
+		customtests.Assert(t, ok, "field %s not found", name)
+		return f
+	}
+
+	t.Run("Test 1: tagged fields", func(t *testing.T) {
+		customtests.Equals(t, "IS_ACTIVE", FieldKey(field("IsActive"), "env"))
+		customtests.Equals(t, "is_active", FieldKey(field("IsActive"), "json"))
+		customtests.Equals(t, "NO1PRIORITY", FieldKey(field("PriorityExample"), "env"))
+		customtests.Equals(t, "no1priority", FieldKey(field("PriorityExample"), "JSON"))
+		customtests.Equals(t, "yaho", FieldKey(field("JsonTagExample"), "json"))
+	})
+
+	t.Run("Test 2: untagged fields", func(t *testing.T) {
+		f, _ := reflect.TypeOf(Simple{}).FieldByName("SimpleC")
+		customtests.Equals(t, "SIMPLE_C", FieldKey(f, "env"))
+		customtests.Equals(t, "simple_c", FieldKey(f, "json"))
+	})
+
+	t.Run("Test 3: nested and excluded fields", func(t *testing.T) {
+		f, _ := reflect.TypeOf(Simple3{}).FieldByName("Database")
+		customtests.Equals(t, "DB", FieldKey(f, "env"))
+
+		type Ignored struct {
+			Secret string `config:"-"`
+		}
+		f, _ = reflect.TypeOf(Ignored{}).FieldByName("Secret")
+		customtests.Equals(t, "", FieldKey(f, "env"))
+		customtests.Equals(t, "", FieldKey(f, "json"))
+		customtests.Equals(t, "", FieldKey(f, "yaml"))
+	})
+
+	t.Run("Test 4: a key named differently per format", func(t *testing.T) {
+		type Server struct {
+			Port   int    `config:"port" env:"SERVER_PORT" json:"listen_port"`
+			Secret string `config:"secret" json:"-"`
+		}
+		typ := reflect.TypeOf(Server{})
+		customtests.Equals(t, "SERVER_PORT", FieldKey(typ.Field(0), "env"))
+		customtests.Equals(t, "port", FieldKey(typ.Field(0), "json"))
+		customtests.Equals(t, "SECRET", FieldKey(typ.Field(1), "env"))
+		customtests.Equals(t, "secret", FieldKey(typ.Field(1), "json"))
+
+		gt := NewGathuk[Server]()
+		customtests.OK(t, gt.LoadConfigString(`{"port": 8080, "secret": "s3cret"}`, "json"))
+		customtests.Equals(t, Server{Port: 8080, Secret: "s3cret"}, gt.GetConfig())
+	})
+}
@@ -0,0 +1,104 @@
+// Package gathuk
+package gathuk
+
+import (
+	"reflect"
+
+	"github.com/ahyalfan/gathuk/internal/encoding/dotenv"
+	"github.com/ahyalfan/gathuk/internal/encoding/json"
+	"github.com/ahyalfan/gathuk/shared"
+)
+
+// fieldInfo describes a leaf field of a configuration struct type together
+// with the keys it resolves to in each built-in format.
+type fieldInfo struct {
+	Path    string              // Go field path, e.g. "Database.Host"
+	EnvKey  string              // Full dotenv key, e.g. "DATABASE_HOST"
+	EnvPath []string            // Segments of EnvKey, e.g. ["DATABASE", "HOST"]
+	JSONKey string              // Dotted JSON key path, e.g. "database.host"
+	Index   []int               // Index sequence for reflect.Value.FieldByIndex
+	Field   reflect.StructField // The leaf struct field
+}
+
+// typeFields walks a struct type and returns its leaf fields in declaration
+// order, recursing into nested structs (and pointers to structs) the same
+// way the built-in codecs do.
+//
+// Fields excluded from both formats (`config:"-"`), catch-all maps and
+// unexported fields are skipped. Recursive struct types are not followed.
+//
+// Parameters:
+//   - t: The struct type to walk
+//
+// Returns the leaf fields, or nil if t is not a struct type.
+func typeFields(t reflect.Type) []fieldInfo {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var fields []fieldInfo
+	walkTypeFields(t, fieldInfo{}, map[reflect.Type]bool{t: true}, &fields)
+	return fields
+}
+
+// walkTypeFields appends the leaf fields of t to fields, prefixing paths and
+// keys with those of parent.
+func walkTypeFields(t reflect.Type, parent fieldInfo, seen map[reflect.Type]bool, fields *[]fieldInfo) {
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		// fields of embedded structs are promoted to the parent level
+		if shared.IsPromoted(structField, string(shared.GetTagNestedName()), string(shared.GetTagName()), "env", "json") {
+			promoted := parent
+			promoted.Index = append(append([]int{}, parent.Index...), i)
+			// an inline field keeps its keys flat but is still a step of the Go path
+			if !structField.Anonymous {
+				promoted.Path = joinKey(parent.Path, structField.Name, ".")
+			}
+			walkTypeFields(structField.Type, promoted, seen, fields)
+			continue
+		}
+
+		if !structField.IsExported() || shared.IsCatchAll(structField) {
+			continue
+		}
+
+		envKey := dotenv.FieldKey(structField)
+		jsonKey := json.FieldKey(structField)
+		if envKey == "" && jsonKey == "" {
+			continue
+		}
+
+		info := fieldInfo{
+			Path:    joinKey(parent.Path, structField.Name, "."),
+			EnvKey:  joinKey(parent.EnvKey, envKey, "_"),
+			EnvPath: append(append([]string{}, parent.EnvPath...), envKey),
+			JSONKey: joinKey(parent.JSONKey, jsonKey, "."),
+			Index:   append(append([]int{}, parent.Index...), i),
+			Field:   structField,
+		}
+
+		nested := structField.Type
+		if nested.Kind() == reflect.Ptr && nested.Elem().Kind() == reflect.Struct {
+			nested = nested.Elem()
+		}
+		if nested.Kind() == reflect.Struct && !shared.IsScalarStruct(nested) {
+			if seen[nested] {
+				continue
+			}
+			seen[nested] = true
+			walkTypeFields(nested, info, seen, fields)
+			delete(seen, nested)
+			continue
+		}
+
+		*fields = append(*fields, info)
+	}
+}
+
+// joinKey joins a parent key and a child key with sep, omitting the separator
+// when the parent is empty.
+func joinKey(parent, key, sep string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + sep + key
+}
@@ -0,0 +1,98 @@
+// Package gathuk
+package gathuk
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+
+	"github.com/ahyalfan/gathuk/internal/encoding/dotenv"
+	"github.com/ahyalfan/gathuk/option"
+)
+
+// BindFlags overrides the loaded configuration with the command-line flags of
+// fs that were set explicitly, so flags take precedence over files and the
+// environment. Call it after loading and after fs.Parse.
+//
+// A flag applies to the field whose .env or JSON key matches its name,
+// ignoring case and treating "-", "_" and "." alike: "port" sets Port, and
+// "db-host", "db_host" or "db.host" set Database.Host for a field tagged
+// `config:"db"`. Only flags visited by fs.Visit are used, so flags left at
+// their default value never clobber values from files. Flags without a
+// matching field are ignored.
+//
+// Flag values are converted like .env values. When a value cannot be
+// converted or the result fails a transform or validation, the error is
+// logged and the configuration is left unchanged. Nil pointers to structs on
+// the way to a bound field are allocated.
+//
+// Parameters:
+//   - fs: The parsed flag set, e.g. flag.CommandLine
+//
+// Example:
+//
+//	port := flag.Int("port", 8080, "listen port")
+//	flag.String("db-host", "localhost", "database host")
+//	flag.Parse()
+//
+//	gt := gathuk.NewGathuk[Config]()
+//	_ = gt.LoadConfigFiles("config.env")
+//	gt.BindFlags(flag.CommandLine) // -db-host=prod overrides DB_HOST
+func (g *Gathuk[T]) BindFlags(fs *flag.FlagSet) {
+	byName := make(map[string]fieldInfo)
+	for _, f := range typeFields(reflect.TypeOf(&g.value).Elem()) {
+		if f.EnvKey == "" {
+			continue
+		}
+		byName[flagKey(f.EnvKey)] = f
+		if f.JSONKey != "" {
+			byName[flagKey(f.JSONKey)] = f
+		}
+	}
+
+	var build []byte
+	var bound []fieldInfo
+	fs.Visit(func(fl *flag.Flag) {
+		f, ok := byName[flagKey(fl.Name)]
+		if !ok {
+			return
+		}
+		build = append(build, strings.ToUpper(f.EnvKey)...)
+		build = append(build, '=')
+		build = append(build, dotenv.QuoteValue(fl.Value.String())...)
+		build = append(build, '\n')
+		bound = append(bound, f)
+	})
+	if len(bound) == 0 {
+		return
+	}
+
+	var flags T
+	cdc := &dotenv.Codec[T]{}
+	cdc.ApplyDecodeOption(&option.DecodeOption{})
+	if err := cdc.Decode(build, &flags); err != nil {
+		g.logger.Error("bind flags", "error", err)
+		return
+	}
+
+	next := g.current()
+	dv := reflect.ValueOf(&next).Elem()
+	sv := reflect.ValueOf(&flags).Elem()
+	for _, f := range bound {
+		s, err := sv.FieldByIndexErr(f.Index)
+		if err != nil {
+			continue
+		}
+		fieldByIndexAlloc(dv, f.Index).Set(s)
+	}
+
+	if err := g.commit(next); err != nil {
+		g.logger.Error("bind flags", "error", err)
+	}
+}
+
+// flagKey normalizes a flag name or config key for matching, e.g. "db-host",
+// "DB_HOST" and "db.host" all become "db_host".
+func flagKey(name string) string {
+	return strings.NewReplacer("-", "_", ".", "_").Replace(strings.ToLower(name))
+}
@@ -0,0 +1,99 @@
+// Package gathuk
+package gathuk
+
+import (
+	"flag"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+func TestGathukBindFlags(t *testing.T) {
+	newFlagSet := func() *flag.FlagSet {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		fs.Int("simple-e", 1, "")
+		fs.Bool("debug-c", false, "")
+		fs.String("db.user", "root", "")
+		fs.String("verbose", "", "")
+		return fs
+	}
+
+	t.Run("Test 1: set flags override the file", func(t *testing.T) {
+		gt := NewGathuk[Simple2]()
+		customtests.OK(t, gt.LoadConfig(strings.NewReader(`{"simple_e": 5, "debug_c": true, "db": {"user": "file"}}`), "json"))
+
+		fs := newFlagSet()
+		customtests.OK(t, fs.Parse([]string{"-simple-e=9", "-db.user=flag", "-verbose=x"}))
+		gt.BindFlags(fs)
+
+		got := gt.GetConfig()
+		customtests.Equals(t, 9, got.Simplee)
+		customtests.Equals(t, "flag", got.Database.User)
+		// not set on the command line, so the file value stays
+		customtests.Equals(t, true, got.Debug)
+	})
+
+	t.Run("Test 2: unset flags keep file values", func(t *testing.T) {
+		gt := NewGathuk[Simple2]()
+		customtests.OK(t, gt.LoadConfig(strings.NewReader(`{"simple_e": 5, "db": {"user": "file"}}`), "json"))
+
+		fs := newFlagSet()
+		customtests.OK(t, fs.Parse(nil))
+		gt.BindFlags(fs)
+
+		customtests.Equals(t, 5, gt.GetConfig().Simplee)
+		customtests.Equals(t, "file", gt.GetConfig().Database.User)
+	})
+
+	t.Run("Test 3: invalid value leaves the config unchanged", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+		gt.SetLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		customtests.OK(t, gt.LoadConfig(strings.NewReader(`{"simple_c": "file", "simple_e": 5}`), "json"))
+
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("simple_e", "", "")
+		fs.String("SIMPLE-C", "", "")
+		customtests.OK(t, fs.Parse([]string{"-simple_e=abc", "-SIMPLE-C=flag"}))
+		gt.BindFlags(fs)
+
+		customtests.Equals(t, Simple{SimpleC: "file", SimpleE: 5}, gt.GetConfig())
+	})
+
+	t.Run("Test 4: flags allocate nil pointers to structs", func(t *testing.T) {
+		type Config struct {
+			Port int
+			DB   *Database `config:"db"`
+		}
+		gt := NewGathuk[Config]()
+		customtests.OK(t, gt.LoadConfig(strings.NewReader(`{"port": 1}`), "json"))
+		customtests.Assert(t, gt.GetConfig().DB == nil, "expected a nil DB before binding")
+
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("db-user", "", "")
+		customtests.OK(t, fs.Parse([]string{"-db-user=flag"}))
+		gt.BindFlags(fs)
+
+		got := gt.GetConfig()
+		customtests.Equals(t, 1, got.Port)
+		customtests.Assert(t, got.DB != nil, "expected DB to be allocated")
+		customtests.Equals(t, "flag", got.DB.User)
+	})
+
+	t.Run("Test 5: transforms run on bound flags", func(t *testing.T) {
+		gt := NewGathuk[Simple2]()
+		gt.AddTransform(func(c *Simple2) error {
+			c.Database.User = strings.ToUpper(c.Database.User)
+			return nil
+		})
+
+		fs := newFlagSet()
+		customtests.OK(t, fs.Parse([]string{"-db.user=flag"}))
+		gt.BindFlags(fs)
+
+		customtests.Equals(t, "FLAG", gt.GetConfig().Database.User)
+	})
+}
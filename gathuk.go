@@ -23,15 +23,24 @@ package gathuk
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
+	"sync"
+	"text/template"
+	"time"
 
 	"github.com/ahyalfan/gathuk/option"
+	"github.com/ahyalfan/gathuk/shared"
 )
 
 // Gathuk is the main configuration manager that handles loading, parsing,
@@ -65,9 +74,52 @@ type Gathuk[T any] struct {
 	// loaded when LoadConfigFiles is called without arguments
 	ConfigFiles []string
 
+	// ConfigFilesOptional makes LoadConfigFiles skip base files from
+	// ConfigFiles that do not exist. Files passed to LoadConfigFiles itself
+	// are always required
+	ConfigFilesOptional bool
+
+	// configPaths lists the directories searched by ReadInConfig
+	configPaths []string
+	// configName is the base name of the file ReadInConfig looks for
+	configName string
+
+	// ForceOverride makes every key present in a later source override the
+	// current value, even when the new value is the zero value of its type
+	// (e.g. DEBUG=false or PORT=0). By default zero values are not merged.
+	// For a slice T it replaces the slice instead of appending to it.
+	ForceOverride bool
+
 	// value stores the parsed and merged configuration struct
 	value T
 
+	// comments stores the comment attached to each key of the loaded
+	// sources, for codecs that preserve comments
+	comments map[string]string
+
+	// transforms holds the transforms registered with AddTransform, run in
+	// order after every load
+	transforms []func(*T) error
+
+	// migrations holds the migrations registered with RegisterMigration,
+	// keyed by the version they upgrade from
+	migrations map[int]func(map[string]any) map[string]any
+
+	// WatchInterval is how often WatchChan polls the loaded files for
+	// changes. Defaults to one second when zero
+	WatchInterval time.Duration
+
+	// files lists the config files loaded so far, in load order, so they
+	// can be watched and reloaded
+	files []string
+
+	// onChange holds the callbacks registered with OnChange
+	onChange []func(old, new T)
+
+	// mu guards value and onChange against concurrent reloads by WatchChan.
+	// Loaders build the next value on a copy and only lock to swap it in
+	mu sync.RWMutex
+
 	// CodecRegistry manages encoders and decoders for different file formats.
 	// By default, it includes support for .env files
 	CodecRegistry option.CodecRegistry[T]
@@ -96,7 +148,8 @@ func (fn optionFunc[T]) apply(g *Gathuk[T]) {
 // The returned instance includes:
 //   - A default codec registry with support for .env files
 //   - A default logger writing to stdout
-//   - Empty configuration ready to be populated
+//   - A configuration holding the `default` tag values of its fields, e.g.
+//     `default:"8080"`, and zero values elsewhere
 //
 // Type parameter T should be a struct type representing your application's configuration.
 //
@@ -112,6 +165,29 @@ func NewGathuk[T any]() *Gathuk[T] {
 	g := &Gathuk[T]{}
 	g.CodecRegistry = NewDefaultCodecRegister[T]()
 	g.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // default slog
+	g.value = g.defaultValue()
+	return g
+}
+
+// SetLogger replaces the logger used for internal logging.
+//
+// By default Gathuk logs to stdout with a text handler. Passing nil silences
+// internal logging entirely.
+//
+// Returns the Gathuk instance for method chaining.
+//
+// Example:
+//
+//	// Log as JSON to stderr
+//	gt.SetLogger(slog.New(slog.NewJSONHandler(os.Stderr, nil)))
+//
+//	// Silence internal logging
+//	gt.SetLogger(nil)
+func (g *Gathuk[T]) SetLogger(l *slog.Logger) *Gathuk[T] {
+	if l == nil {
+		l = slog.New(slog.DiscardHandler)
+	}
+	g.logger = l
 	return g
 }
 
@@ -137,6 +213,34 @@ func (g *Gathuk[T]) SetCustomCodecRegistry(c option.CodecRegistry[T]) *Gathuk[T]
 	return g
 }
 
+// RegisterCodec registers a codec for a file format on the current codec
+// registry, replacing any codec already registered for that format.
+//
+// This is a shortcut for the common case of adding one format without
+// building a registry first. The registry must be able to register codecs,
+// like DefaultCodecRegistry; otherwise the error is logged and the registry
+// is left unchanged.
+//
+// Returns the Gathuk instance for method chaining.
+//
+// Example:
+//
+//	gt := gathuk.NewGathuk[Config]().
+//	    RegisterCodec("yaml", &YAMLCodec[Config]{})
+//
+//	err := gt.LoadConfigFiles("config.yaml")
+func (g *Gathuk[T]) RegisterCodec(format string, codec option.Codec[T]) *Gathuk[T] {
+	r, ok := g.CodecRegistry.(interface {
+		RegisterCodec(format string, codec option.Codec[T])
+	})
+	if !ok {
+		g.logger.Error(fmt.Sprintf("register codec %q: codec registry %T does not support registering codecs", format, g.CodecRegistry))
+		return g
+	}
+	r.RegisterCodec(format, codec)
+	return g
+}
+
 // SetDecodeOption sets the decode options for a specific file format.
 // These options control how configuration values are read and merged.
 //
@@ -192,6 +296,9 @@ func (g *Gathuk[T]) SetEncodeOption(format string, encodeOption *option.EncodeOp
 // This method does not load the files immediately; it only stores the file paths.
 // Call LoadConfigFiles to actually load and parse the configuration.
 //
+// Base files set this way are required: LoadConfigFiles fails if one of them
+// is missing. Use SetConfigFilesOptional for base files that may be absent.
+//
 // Parameters:
 //   - srcFiles: Variable number of file paths to set as base configuration files
 //
@@ -202,6 +309,25 @@ func (g *Gathuk[T]) SetEncodeOption(format string, encodeOption *option.EncodeOp
 //	err := gt.LoadConfigFiles("override.env")
 func (g *Gathuk[T]) SetConfigFiles(srcFiles ...string) {
 	g.ConfigFiles = srcFiles
+	g.ConfigFilesOptional = false
+}
+
+// SetConfigFilesOptional is like SetConfigFiles, but the base files are
+// optional: LoadConfigFiles skips those that do not exist (and glob patterns
+// that match nothing) instead of failing. Files passed to LoadConfigFiles
+// remain required.
+//
+// Parameters:
+//   - srcFiles: Variable number of file paths to set as optional base files
+//
+// Example:
+//
+//	gt.SetConfigFilesOptional("/etc/myapp/config.env", "local.env")
+//	// loads whichever base files exist, then app.env which must exist
+//	err := gt.LoadConfigFiles("app.env")
+func (g *Gathuk[T]) SetConfigFilesOptional(srcFiles ...string) {
+	g.ConfigFiles = srcFiles
+	g.ConfigFilesOptional = true
 }
 
 // LoadConfigFiles loads configuration from one or more files and merges them
@@ -209,17 +335,33 @@ func (g *Gathuk[T]) SetConfigFiles(srcFiles ...string) {
 // overriding values from earlier ones.
 //
 // If no files are specified and no base files are set via SetConfigFiles,
-// this method will attempt to load from ".env" by default.
+// this method will attempt to load from ".env" by default. Base files set via
+// SetConfigFilesOptional are skipped when they do not exist.
+//
+// File paths may contain glob patterns (e.g. "conf.d/*.env"). Matches of a
+// pattern are loaded in lexical order, and a pattern that matches no file
+// results in an error.
+//
+// Files ending in ".gz" are gzip decompressed while loading, and their format
+// is taken from the extension before it, e.g. "json" for "config.json.gz".
 //
 // The merge behavior:
 //   - Later files override earlier files
-//   - Zero values are not merged (existing non-zero values are preserved)
+//   - Zero values are not merged (existing non-zero values are preserved),
+//     unless ForceOverride is set
 //   - Nested structs are merged recursively
 //
 // Parameters:
 //   - srcFiles: Variable number of configuration file paths to load
 //
-// Returns an error if any file cannot be read or parsed.
+// After all files are merged, the configuration is validated against the
+// validation tags of T (e.g. `oneof:"80 443"`).
+//
+// The formats of all files are checked before any of them is loaded, so a file
+// with an unsupported extension fails the call without merging the others.
+//
+// Returns an error if any file has an unsupported format, cannot be read or
+// parsed, or validation fails.
 //
 // Example:
 //
@@ -232,14 +374,162 @@ func (g *Gathuk[T]) SetConfigFiles(srcFiles ...string) {
 //	// Load base files plus additional file
 //	gt.SetConfigFiles("base.env")
 //	err := gt.LoadConfigFiles("override.env")
+//
+//	// Load every drop-in file matching a pattern
+//	err := gt.LoadConfigFiles("conf.d/*.env")
 func (g *Gathuk[T]) LoadConfigFiles(srcFiles ...string) error {
-	srcFiles = resolveFilenames(append(g.ConfigFiles, srcFiles...)...)
+	return g.LoadConfigFilesContext(context.Background(), srcFiles...)
+}
+
+// LoadConfigFilesContext is like LoadConfigFiles, but honors ctx while reading
+// each file and between files.
+//
+// If ctx is cancelled or its deadline expires, loading stops promptly and
+// ctx.Err() is returned. Files loaded before cancellation stay merged.
+//
+// Parameters:
+//   - ctx: Context controlling cancellation and timeout
+//   - srcFiles: Variable number of configuration file paths to load
+//
+// Returns ctx.Err() if the context is done, or any error LoadConfigFiles
+// would return.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//
+//	err := gt.LoadConfigFilesContext(ctx, "/mnt/shared/config.env")
+func (g *Gathuk[T]) LoadConfigFilesContext(ctx context.Context, srcFiles ...string) error {
+	base := g.ConfigFiles
+	if g.ConfigFilesOptional {
+		base = existingFiles(base)
+		// every optional base file is missing and nothing else was asked for
+		if len(base) == 0 && len(srcFiles) == 0 && len(g.ConfigFiles) > 0 {
+			return g.reapply()
+		}
+	}
+
+	srcFiles, err := resolveFilenames(append(slices.Clone(base), srcFiles...)...)
+	if err != nil {
+		return err
+	}
+	return g.loadFiles(ctx, srcFiles)
+}
+
+// LoadOptionalConfigFiles is like LoadConfigFiles for files that may not
+// exist, such as a developer's local.env override.
+//
+// Files that do not exist (and glob patterns that match nothing) are skipped;
+// files that exist but cannot be read or parsed still fail the call. Only the
+// given files are loaded: base files from SetConfigFiles are not included,
+// and no ".env" default is assumed.
+//
+// Parameters:
+//   - srcFiles: Variable number of optional configuration file paths to load
+//
+// Returns an error if an existing file has an unsupported format, cannot be
+// read or parsed, or validation fails.
+//
+// Example:
+//
+//	err := gt.LoadConfigFiles("app.env")          // required
+//	err = gt.LoadOptionalConfigFiles("local.env") // only if present
+func (g *Gathuk[T]) LoadOptionalConfigFiles(srcFiles ...string) error {
+	existing := existingFiles(srcFiles)
+	if len(existing) == 0 {
+		return g.reapply()
+	}
+
+	files, err := resolveFilenames(existing...)
+	if err != nil {
+		return err
+	}
+	return g.loadFiles(context.Background(), files)
+}
+
+// loadFiles loads resolved config files in order, then validates the result.
+// The formats of all files are checked before any of them is loaded.
+//
+// Parameters:
+//   - ctx: Context controlling cancellation while reading the files
+//   - srcFiles: The file paths to load, without glob patterns
+//
+// Returns the first error encountered.
+func (g *Gathuk[T]) loadFiles(ctx context.Context, srcFiles []string) error {
+	// fail fast before merging anything if a file has an unsupported format
+	for _, filename := range srcFiles {
+		if err := g.checkFileFormat(filename); err != nil {
+			return err
+		}
+	}
+	next := g.current()
 	for _, filename := range srcFiles {
-		err := g.loadFile(filename, &g.value)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := g.loadFile(ctx, filename, &next)
 		if err != nil {
 			return err
 		}
 	}
+	if err := g.commit(next); err != nil {
+		return err
+	}
+	for _, filename := range srcFiles {
+		g.trackFile(filename)
+	}
+	return nil
+}
+
+// LoadConfigDir loads every configuration file in a directory and merges them
+// into the configuration struct, following the common "conf.d/" drop-in pattern.
+//
+// Files are loaded in lexical order of their names, so prefixing them with a
+// number (e.g. "10-base.env", "20-local.json") controls precedence. Only files
+// whose extension has a registered decoder are loaded; other files and
+// subdirectories are skipped.
+//
+// Parameters:
+//   - dir: Path to the directory containing configuration files
+//
+// Returns an error if the directory cannot be read or any file fails to load.
+//
+// Example:
+//
+//	// conf.d/10-base.env, conf.d/20-override.json
+//	err := gt.LoadConfigDir("conf.d")
+func (g *Gathuk[T]) LoadConfigDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	next := g.current()
+	var loaded []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		ext, _ := fileFormat(entry.Name())
+		if _, err := g.CodecRegistry.Decoder(ext); err != nil {
+			continue
+		}
+
+		filename := filepath.Join(dir, entry.Name())
+		err := g.loadFile(context.Background(), filename, &next)
+		if err != nil {
+			return err
+		}
+		loaded = append(loaded, filename)
+	}
+	if err := g.commit(next); err != nil {
+		return err
+	}
+	for _, filename := range loaded {
+		g.trackFile(filename)
+	}
 	return nil
 }
 
@@ -265,75 +555,441 @@ func (g *Gathuk[T]) LoadConfigFiles(srcFiles ...string) error {
 //
 //	err = gt.LoadConfig(file, "env")
 //
-//	// Or from a string
+//	// Or from a string, see also LoadConfigString
 //	config := strings.NewReader("PORT=8080\nHOST=localhost")
 //	err = gt.LoadConfig(config, "env")
 func (g *Gathuk[T]) LoadConfig(src io.Reader, format string) error {
-	err := g.load(src, format, &g.value)
+	return g.LoadConfigContext(context.Background(), src, format)
+}
+
+// LoadConfigString is like LoadConfig, but reads the configuration from a
+// string, which is handy for tests and examples with inline configuration.
+//
+// Parameters:
+//   - s: The configuration data
+//   - format: The format of the configuration (e.g., "env", "json")
+//
+// Returns an error if parsing or validating fails.
+//
+// Example:
+//
+//	err := gt.LoadConfigString("PORT=8080\nHOST=localhost", "env")
+func (g *Gathuk[T]) LoadConfigString(s, format string) error {
+	return g.LoadConfig(strings.NewReader(s), format)
+}
+
+// LoadConfigContext is like LoadConfig, but honors ctx while reading src.
+//
+// This is useful for slow readers such as network connections or pipes: if
+// ctx is cancelled or its deadline expires before src is fully read, the call
+// returns ctx.Err() promptly without waiting for the reader.
+//
+// Parameters:
+//   - ctx: Context controlling cancellation and timeout
+//   - src: io.Reader containing the configuration data
+//   - format: The format of the configuration (e.g., "env", "json")
+//
+// Returns ctx.Err() if the context is done, or any error LoadConfig would
+// return.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+//	defer cancel()
+//
+//	resp, err := http.Get("http://config-server/app.json")
+//	if err != nil {
+//	    return err
+//	}
+//	defer resp.Body.Close()
+//
+//	err = gt.LoadConfigContext(ctx, resp.Body, "json")
+func (g *Gathuk[T]) LoadConfigContext(ctx context.Context, src io.Reader, format string) error {
+	next := g.current()
+	if err := g.load(ctx, src, format, &next); err != nil {
+		return err
+	}
+	return g.commit(next)
+}
+
+// AppendConfig layers configuration from an io.Reader over the current
+// configuration.
+//
+// src is decoded on top of a copy of the current configuration, which is then
+// merged like any other source: keys absent from src keep their values,
+// nested structs (including pointers to structs) are merged field by field
+// and maps are updated key by key. A slice field set by src is replaced by
+// the decoded elements; only a configuration that is itself a slice (T is
+// []E) gets them appended. A `default:"true"` bool can still be turned off
+// by the overlay. The current configuration is only replaced once src has
+// been fully decoded, so overlays from non-file sources can be stacked
+// programmatically.
+//
+// AppendConfig is LoadConfig under a name that reads better when stacking
+// overlays; both go through the same load path.
+//
+// Parameters:
+//   - src: io.Reader containing the overlay
+//   - format: The format of the overlay (e.g., "env", "json")
+//
+// Returns an error if reading, parsing or validating fails.
+//
+// Example:
+//
+//	err := gt.LoadConfigFiles("config.env") // PORT=8080, HOST=localhost
+//	err = gt.AppendConfig(strings.NewReader(`{"port": 9090}`), "json")
+//	// Port: 9090, Host: "localhost"
+func (g *Gathuk[T]) AppendConfig(src io.Reader, format string) error {
+	return g.LoadConfigContext(context.Background(), src, format)
+}
+
+// LoadFragment loads a configuration fragment from an io.Reader and merges it
+// into the configuration struct as if all of its keys were declared under prefix.
+//
+// This makes it possible to assemble a large configuration from per-service
+// fragment files, where each file only contains the keys of its own subtree.
+// The prefix is a dotted path of config keys (e.g. "services.api"), which is
+// applied as "SERVICES_API_" for .env fragments and as nested objects for JSON
+// fragments.
+//
+// Parameters:
+//   - src: io.Reader containing the fragment data
+//   - format: The format of the fragment ("env" or "json")
+//   - prefix: Dotted path of the subtree the fragment belongs to
+//
+// Returns an error if the format does not support fragments, or reading or
+// parsing fails.
+//
+// Example:
+//
+//	type Config struct {
+//	    Billing Service `config:"billing"`
+//	    Search  Service `config:"search"`
+//	}
+//
+//	// billing.env contains HOST=billing.local
+//	err := gt.LoadFragment(billingFile, "env", "billing")
+//	// search.json contains {"host": "search.local"}
+//	err = gt.LoadFragment(searchFile, "json", "search")
+func (g *Gathuk[T]) LoadFragment(src io.Reader, format, prefix string) error {
+	by, err := readAll(context.Background(), src)
 	if err != nil {
 		return err
 	}
-	return nil
+
+	by, err = prefixFragment(by, format, prefix)
+	if err != nil {
+		return err
+	}
+
+	next := g.current()
+	err = g.load(context.Background(), bytes.NewReader(by), format, &next)
+	if err != nil {
+		return err
+	}
+	return g.commit(next)
+}
+
+// LoadTemplate renders a configuration template with text/template and merges
+// the result into the configuration struct.
+//
+// The whole file is templated, which makes it easy to embed a config template
+// in the binary and fill it in at startup (from build-time variables, the
+// environment, flags, ...). The rendered output is decoded like LoadConfig.
+//
+// Parameters:
+//   - tmpl: The template text
+//   - format: The format of the rendered configuration (e.g., "env", "json")
+//   - data: The data passed to the template
+//
+// Returns an error if the template cannot be parsed or executed, or the
+// rendered configuration cannot be loaded.
+//
+// Example:
+//
+//	//go:embed config.env.tmpl
+//	var configTemplate string
+//
+//	err := gt.LoadTemplate(configTemplate, "env", map[string]any{
+//	    "Port": os.Getenv("PORT"),
+//	})
+//
+//	// config.env.tmpl:
+//	// PORT={{.Port}}
+func (g *Gathuk[T]) LoadTemplate(tmpl string, format string, data any) error {
+	t, err := template.New(format).Parse(tmpl)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	err = t.Execute(&buf, data)
+	if err != nil {
+		return err
+	}
+
+	return g.LoadConfig(&buf, format)
+}
+
+// LoadFromEnv populates the configuration struct from the OS environment
+// only, for deployments where no config file exists at all.
+//
+// Keys are resolved like for .env files (e.g. DB_HOST for a field Host nested
+// under `config:"db"`), and the result is merged and validated like any other
+// source. The codec registered for "env" decodes them with the options set
+// for "env", or the global decode options, with AutomaticEnv always enabled.
+// The registered codec keeps its own options: codecs implementing Clone
+// decode on a clone, others get their previous options applied back once
+// decoding is done.
+//
+// Returns an error if a variable cannot be converted to its field type or
+// validation fails.
+//
+// Example:
+//
+//	// PORT=8080 DB_HOST=db.internal ./myapp
+//	gt := gathuk.NewGathuk[Config]()
+//	if err := gt.LoadFromEnv(); err != nil {
+//	    log.Fatal(err)
+//	}
+func (g *Gathuk[T]) LoadFromEnv() error {
+	dc, err := g.decoder("env")
+	if err != nil {
+		return err
+	}
+	prev := *g.decodeOption("env")
+	do := prev
+	do.AutomaticEnv = true
+	do.PreferFileOverEnv = false
+	dc.ApplyDecodeOption(&do)
+	if _, ok := dc.(cloner[T]); !ok {
+		// dc is the registered codec itself, so put its options back
+		defer dc.ApplyDecodeOption(&prev)
+	}
+
+	cur := g.current()
+	next := cur
+	if err := dc.Decode(nil, &next); err != nil {
+		return err
+	}
+	if err := g.merge(&cur, &next); err != nil {
+		return err
+	}
+	return g.commit(cur)
 }
 
 // loadFile is an internal method that opens and loads a single configuration file.
 // It automatically determines the file format from the file extension.
 //
 // Parameters:
+//   - ctx: Context controlling cancellation while reading the file
 //   - filename: Path to the configuration file
 //
-// Returns the parsed configuration struct and any error encountered.
-func (g *Gathuk[T]) loadFile(filename string, val *T) error {
-	f, err := os.Open(filename)
-	if err != nil {
+// Returns any error encountered. Decoding errors are prefixed with the file
+// name and format, e.g. `load "config.json" (json): ...`.
+func (g *Gathuk[T]) loadFile(ctx context.Context, filename string, val *T) error {
+	if err := g.checkFileFormat(filename); err != nil {
 		return err
 	}
 
+	f, err := openFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return shared.WrapError(err, ErrFileNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("load %q: %w", filename, err)
+	}
+
 	defer f.Close()
 
-	ext := strings.Trim(filepath.Ext(filename), ".")
+	ext, _ := fileFormat(filename)
 
-	return g.load(f, ext, val)
+	// name the file, so a failure among many overlaid files can be traced
+	if err := g.load(ctx, f, ext, val); err != nil {
+		return fmt.Errorf("load %q (%s): %w", filename, ext, err)
+	}
+	return nil
+}
+
+// checkFileFormat checks that a decoder is registered for the format implied by
+// the extension of filename.
+//
+// Parameters:
+//   - filename: Path to the configuration file
+//
+// Returns an error naming both the file and the format if no decoder is found.
+func (g *Gathuk[T]) checkFileFormat(filename string) error {
+	ext, _ := fileFormat(filename)
+	if _, err := g.CodecRegistry.Decoder(ext); err != nil {
+		// custom registries may not mark the error themselves
+		return fmt.Errorf("load %q: unsupported format %q: %w", filename, ext, shared.WrapError(err, ErrFormatNotSupported))
+	}
+	return nil
 }
 
 // load is an internal method that reads and parses configuration data from an io.Reader.
 //
 // Parameters:
+//   - ctx: Context controlling cancellation while reading src
 //   - src: io.Reader containing the configuration data
 //   - format: The format of the configuration data
 //
 // Returns the parsed configuration struct and any error encountered.
-func (g *Gathuk[T]) load(src io.Reader, format string, val *T) error {
-	var buf bytes.Buffer
-
-	_, err := io.Copy(&buf, src)
+func (g *Gathuk[T]) load(ctx context.Context, src io.Reader, format string, val *T) error {
+	by, err := readAll(ctx, src)
+	if err != nil {
+		return err
+	}
+	by, err = g.migrate(by, format)
 	if err != nil {
 		return err
 	}
 
-	by := buf.Bytes()
+	dc, err := g.decoder(format)
+	if err != nil {
+		return err
+	}
 
-	dc, err := g.CodecRegistry.Decoder(format)
+	// slices are concatenated by merge, so they are decoded on their own
+	var next T
+	if reflect.ValueOf(val).Elem().Kind() != reflect.Slice {
+		next = *val
+	}
+	err = dc.Decode(by, &next)
 	if err != nil {
 		return err
 	}
 
-	if ok := dc.CheckDecodeOption(); !ok {
+	if cm, ok := dc.(commenter); ok {
+		g.addComments(cm.Comments())
+	}
+
+	return g.merge(val, &next)
+}
+
+// cloner is implemented by codecs that can hand out a copy of themselves with
+// the same options, such as the built-in codecs.
+type cloner[T any] interface {
+	Clone() option.Codec[T]
+}
+
+// decoder returns the decoder for format with its decode options resolved:
+// the options set for the format with SetDecodeOption, or the global decode
+// options when none are set.
+//
+// Codecs implementing Clone, such as the built-in ones, are cloned first, so
+// the registered codec is never modified and callers do not share its state.
+// Other codecs are returned as registered, with the global options applied
+// when they have none.
+//
+// Parameters:
+//   - format: The format to decode (e.g., "env", "json")
+//
+// Returns the decoder, or an error if no codec handles the format.
+func (g *Gathuk[T]) decoder(format string) (option.Decoder[T], error) {
+	dc, err := g.CodecRegistry.Decoder(format)
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := dc.(cloner[T]); ok {
+		dc = c.Clone()
+	}
+	if !dc.CheckDecodeOption() {
 		dc.ApplyDecodeOption(&g.globalDecodeOpt)
 	}
+	return dc, nil
+}
+
+// decodeOption returns the decode options used for format: the options set
+// for the format with SetDecodeOption, or the global decode options when none
+// are set.
+//
+// Parameters:
+//   - format: The format (e.g., "env", "json")
+//
+// Returns the decode options; they must not be modified.
+func (g *Gathuk[T]) decodeOption(format string) *option.DecodeOption {
+	if dc, err := g.CodecRegistry.Decoder(format); err == nil && dc.CheckDecodeOption() {
+		if o, ok := dc.(interface{ AppliedDecodeOption() *option.DecodeOption }); ok {
+			return o.AppliedDecodeOption()
+		}
+	}
+	return &g.globalDecodeOpt
+}
 
-	err = dc.Decode(by, val)
+// encoder returns the encoder for format with its encode options resolved,
+// cloning codecs that implement Clone the same way decoder does.
+//
+// Parameters:
+//   - format: The format to encode (e.g., "env", "json")
+//
+// Returns the encoder, or an error if no codec handles the format.
+func (g *Gathuk[T]) encoder(format string) (option.Encoder[T], error) {
+	enc, err := g.CodecRegistry.Encoder(format)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	if c, ok := enc.(cloner[T]); ok {
+		enc = c.Clone()
+	}
+	if !enc.CheckEncodeOption() {
+		enc.ApplyEncodeOption(&g.globalEncodeOpt)
+	}
+	return enc, nil
+}
 
+// merge applies a freshly decoded configuration on top of the current one.
+//
+// The decoded value starts as a copy of the current configuration, so keys
+// absent from the source keep their existing values. When ForceOverride is set
+// the decoded value replaces the current one as-is; otherwise struct fields are
+// merged with mergeStruct so zero values do not clear existing values, except
+// for fields with a `default` tag, which take the decoded value as is so a
+// source can still set them to their zero value (e.g. DEBUG=false).
+// A slice configuration, such as []Record, gets the decoded elements appended,
+// so loading several files concatenates their arrays, and a map configuration
+// gets the decoded keys set over the existing ones. Other non-struct types
+// (any) are always replaced.
+//
+// Parameters:
+//   - dst: Pointer to the current configuration
+//   - src: Pointer to the decoded configuration
+//
+// Returns an error if merging fails.
+func (g *Gathuk[T]) merge(dst, src *T) error {
+	dv := reflect.ValueOf(dst).Elem()
+	if !g.ForceOverride && dv.Kind() == reflect.Slice {
+		dv.Set(reflect.AppendSlice(dv, reflect.ValueOf(src).Elem()))
+		return nil
+	}
+	if !g.ForceOverride && dv.Kind() == reflect.Map {
+		// the decoded map may be the current one, so build a new map
+		merged := reflect.MakeMap(dv.Type())
+		for _, m := range []reflect.Value{dv, reflect.ValueOf(src).Elem()} {
+			iter := m.MapRange()
+			for iter.Next() {
+				merged.SetMapIndex(iter.Key(), iter.Value())
+			}
+		}
+		dv.Set(merged)
+		return nil
+	}
+	if g.ForceOverride || dv.Kind() != reflect.Struct {
+		*dst = *src
+		return nil
+	}
+	if err := g.mergeStruct(dst, src); err != nil {
+		return err
+	}
+	g.keepDecodedDefaults(dst, src)
 	return nil
 }
 
 // WriteConfigFile writes the configuration struct to a file with the specified permissions.
 //
 // The file format is automatically determined from the file extension.
-// If the file already exists, it will be truncated.
+// A ".gz" suffix gzip compresses the output, e.g. "config.json.gz" is written
+// as compressed JSON. If the file already exists, it will be truncated.
 //
 // Parameters:
 //   - dst: Destination file path
@@ -409,14 +1065,16 @@ func (g *Gathuk[T]) writeFile(dst string, mode fs.FileMode, config T) error {
 		}
 	}
 
-	ext := strings.Trim(filepath.Ext(dst), ".")
+	ext, gzipped := fileFormat(dst)
+	if !gzipped {
+		return g.write(f, ext, config)
+	}
 
-	err = g.write(f, ext, config)
-	if err != nil {
+	zw := gzip.NewWriter(f)
+	if err := g.write(zw, ext, config); err != nil {
 		return err
 	}
-
-	return nil
+	return zw.Close()
 }
 
 // write is an internal method that encodes and writes configuration to an io.Writer.
@@ -428,15 +1086,11 @@ func (g *Gathuk[T]) writeFile(dst string, mode fs.FileMode, config T) error {
 //
 // Returns an error if encoding or writing fails.
 func (g *Gathuk[T]) write(out io.Writer, format string, config T) error {
-	enc, err := g.CodecRegistry.Encoder(format)
+	enc, err := g.encoder(format)
 	if err != nil {
 		return err
 	}
 
-	if ok := enc.CheckEncodeOption(); !ok {
-		enc.ApplyEncodeOption(&g.globalEncodeOpt)
-	}
-
 	bys, err := enc.Encode(config)
 	if err != nil {
 		return err
@@ -461,9 +1115,67 @@ func (g *Gathuk[T]) write(out io.Writer, format string, config T) error {
 //	config := gt.GetConfig()
 //	fmt.Printf("Port: %d, Host: %s\n", config.Port, config.Host)
 func (g *Gathuk[T]) GetConfig() T {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
 	return g.value
 }
 
+// current returns a deep copy of the current configuration for a loader to
+// build the next configuration on, without touching the one readers see.
+func (g *Gathuk[T]) current() T {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return deepCopy(g.value)
+}
+
+// commit runs the transforms and validation on next, built by a loader from
+// current, and swaps it in as the current configuration if they succeed.
+//
+// Returns the first transform or validation error, in which case the current
+// configuration is left unchanged.
+func (g *Gathuk[T]) commit(next T) error {
+	if err := g.afterLoad(&next); err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.value = next
+	return nil
+}
+
+// reapply runs the transforms and validation on the current configuration
+// again, for loads that found nothing to read.
+func (g *Gathuk[T]) reapply() error {
+	return g.commit(g.current())
+}
+
+// Reset clears the loaded configuration back to the zero value of T, with the
+// `default` tags of its fields applied.
+//
+// Codecs registered on a DefaultCodecRegistry that keep internal state between
+// calls are reset as well, so a subsequent load starts from scratch instead of
+// merging into the previously loaded values.
+//
+// Example:
+//
+//	err := gt.LoadConfigFiles("test-a.env")
+//	// ...
+//	gt.Reset()
+//	err = gt.LoadConfigFiles("test-b.env") // no values left over from test-a.env
+func (g *Gathuk[T]) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.value = g.defaultValue()
+	g.comments = nil
+	g.files = nil
+
+	if dcr, ok := g.CodecRegistry.(*DefaultCodecRegistry[T]); ok {
+		dcr.resetCodecs()
+	}
+}
+
 // mergeStruct recursively merges configuration from src into dst.
 //
 // The merge behavior:
@@ -488,8 +1200,8 @@ func (g *Gathuk[T]) mergeStruct(dst, src any) error {
 			continue
 		}
 
-		switch df.Kind() {
-		case reflect.Struct:
+		switch {
+		case df.Kind() == reflect.Struct && !shared.IsScalarStruct(df.Type()):
 			if err := g.mergeStruct(df.Addr().Interface(), sf.Addr().Interface()); err != nil {
 				return err
 			}
@@ -2,18 +2,33 @@
 package gathuk
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"math"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ahyalfan/gathuk/internal/encoding/json"
 	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+	"github.com/ahyalfan/gathuk/option"
 )
 
 var (
 	EXAMPLE_ENV_FILE   string = "./example/dotenv/.example.env"
 	EXAMPLE_2_ENV_file string = "./example/dotenv/.example_2.env"
 	EXAMPLE_1_ENV_file string = "./example/dotenv/.example_1.env"
+	EXAMPLE_3_ENV_file string = "./example/dotenv/.example_3.env"
 	EXAMPLE_JSON_file  string = "./example/json/example.json"
+	EXAMPLE_CONF_D_dir string = "./example/conf.d"
+	EXAMPLE_WEIRD_file string = "./example/config.weird"
 )
 
 type Simple struct {
@@ -205,6 +220,702 @@ func TestGathukLoad(t *testing.T) {
 			customtests.Equals(t, "hore", os.Getenv("SIMPLE_C"))
 		})
 	})
+
+	t.Run("Test 5 : Load Gathuk config from glob pattern", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		err := gt.LoadConfigFiles(EXAMPLE_CONF_D_dir + "/*.env")
+		customtests.OK(t, err)
+
+		customtests.Equals(t, "base", gt.GetConfig().SimpleC)
+		customtests.Equals(t, 20, gt.GetConfig().SimpleE)
+
+		gt2 := NewGathuk[Simple]()
+
+		err = gt2.LoadConfigFiles(EXAMPLE_CONF_D_dir + "/*.missing")
+		customtests.Assert(t, err != nil, "expected error for pattern without matches")
+	})
+
+	t.Run("Test 6 : Load Gathuk config from directory", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		err := gt.LoadConfigDir(EXAMPLE_CONF_D_dir)
+		customtests.OK(t, err)
+
+		customtests.Equals(t, "from_json", gt.GetConfig().SimpleC)
+		customtests.Equals(t, 20, gt.GetConfig().SimpleE)
+	})
+
+	t.Run("Test 7 : Later file overriding with zero values", func(t *testing.T) {
+		t.Run("Test 7.1: zero values are not merged by default", func(t *testing.T) {
+			gt := NewGathuk[Simple2]()
+
+			err := gt.LoadConfigFiles(EXAMPLE_ENV_FILE, EXAMPLE_3_ENV_file)
+			customtests.OK(t, err)
+
+			customtests.Equals(t, true, gt.GetConfig().Debug)
+			customtests.Equals(t, 2, gt.GetConfig().Simplee)
+			customtests.Equals(t, "dbtest", gt.GetConfig().Database.User)
+		})
+
+		t.Run("Test 7.2: zero values override with ForceOverride", func(t *testing.T) {
+			gt := NewGathuk[Simple2]()
+			gt.ForceOverride = true
+
+			err := gt.LoadConfigFiles(EXAMPLE_ENV_FILE, EXAMPLE_3_ENV_file)
+			customtests.OK(t, err)
+
+			customtests.Equals(t, false, gt.GetConfig().Debug)
+			customtests.Equals(t, 0, gt.GetConfig().Simplee)
+			customtests.Equals(t, "dbtest", gt.GetConfig().Database.User)
+		})
+	})
+}
+
+type Service struct {
+	Host string
+	Port int
+}
+
+type Services struct {
+	Billing Service `config:"billing"`
+	Search  Service `config:"search"`
+}
+
+func TestGathukLoadConfigString(t *testing.T) {
+	type Server struct {
+		Port int
+		Host string
+	}
+
+	t.Run("Test 1: inline env", func(t *testing.T) {
+		gt := NewGathuk[Server]()
+
+		err := gt.LoadConfigString("PORT=8080\nHOST=localhost", "env")
+		customtests.OK(t, err)
+		customtests.Equals(t, Server{Port: 8080, Host: "localhost"}, gt.GetConfig())
+	})
+
+	t.Run("Test 2: unsupported format", func(t *testing.T) {
+		gt := NewGathuk[Server]()
+
+		err := gt.LoadConfigString("port: 8080", "yaml")
+		customtests.Assert(t, err != nil, "expected error for unsupported format")
+	})
+}
+
+func TestGathukFormatTags(t *testing.T) {
+	type Server struct {
+		Port int    `config:"port" env:"SERVER_PORT" json:"port"`
+		Host string `env:"SERVER_HOST" json:"host"`
+	}
+
+	t.Run("Test 1: env tag in .env files", func(t *testing.T) {
+		gt := NewGathuk[Server]()
+
+		err := gt.LoadConfigString("SERVER_PORT=8080\nSERVER_HOST=localhost\nPORT=9090", "env")
+		customtests.OK(t, err)
+		customtests.Equals(t, Server{Port: 8080, Host: "localhost"}, gt.GetConfig())
+		customtests.Equals(t, "SERVER_PORT", FieldKey(reflect.TypeOf(Server{}).Field(0), "env"))
+	})
+
+	t.Run("Test 2: json tag in JSON files", func(t *testing.T) {
+		gt := NewGathuk[Server]()
+
+		err := gt.LoadConfigString(`{"port": 8080, "host": "localhost"}`, "json")
+		customtests.OK(t, err)
+		customtests.Equals(t, Server{Port: 8080, Host: "localhost"}, gt.GetConfig())
+		customtests.Equals(t, "host", FieldKey(reflect.TypeOf(Server{}).Field(1), "json"))
+	})
+}
+
+func TestGathukLoadFragment(t *testing.T) {
+	t.Run("Test 1: assemble fragments under different prefixes", func(t *testing.T) {
+		gt := NewGathuk[Services]()
+
+		err := gt.LoadFragment(strings.NewReader("HOST=billing.local\nexport PORT=8081\n# HOST=ignored"), "env", "billing")
+		customtests.OK(t, err)
+		err = gt.LoadFragment(strings.NewReader(`{"host": "search.local", "port": 8082}`), "json", "search")
+		customtests.OK(t, err)
+
+		customtests.Equals(t, Services{
+			Billing: Service{Host: "billing.local", Port: 8081},
+			Search:  Service{Host: "search.local", Port: 8082},
+		}, gt.GetConfig())
+	})
+
+	t.Run("Test 2: unsupported fragment format", func(t *testing.T) {
+		gt := NewGathuk[Services]()
+
+		err := gt.LoadFragment(strings.NewReader("host: a"), "yaml", "billing")
+		customtests.Assert(t, err != nil, "expected error for unsupported fragment format")
+	})
+}
+
+func TestGathukLoadTemplate(t *testing.T) {
+	t.Run("Test 1: render and decode", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		err := gt.LoadTemplate("SIMPLE_C={{.Name}}\nSIMPLE_E={{.Port}}", "env", map[string]any{"Name": "tmpl", "Port": 8080})
+		customtests.OK(t, err)
+		customtests.Equals(t, Simple{SimpleC: "tmpl", SimpleE: 8080}, gt.GetConfig())
+	})
+
+	t.Run("Test 2: invalid template", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		err := gt.LoadTemplate("SIMPLE_E={{.Port", "env", nil)
+		customtests.Assert(t, err != nil, "expected template parse error")
+	})
+}
+
+func TestGathukCodecOptions(t *testing.T) {
+	data := `{"HOST": "billing.local", "PORT": 8081}`
+
+	t.Run("Test 1: codec registered with options", func(t *testing.T) {
+		registry := NewDefaultCodecRegister[Service]()
+		registry.RegisterCodecWithOptions("json", &json.Codec[Service]{}, &option.DecodeOption{CaseInsensitiveKeys: true}, nil)
+
+		gt := NewGathuk[Service]()
+		gt.SetCustomCodecRegistry(registry)
+
+		err := gt.LoadConfig(strings.NewReader(data), "json")
+		customtests.OK(t, err)
+		customtests.Equals(t, Service{Host: "billing.local", Port: 8081}, gt.GetConfig())
+	})
+
+	t.Run("Test 2: options set on a built-in codec stick", func(t *testing.T) {
+		gt := NewGathuk[Service]()
+		gt.SetDecodeOption("json", &option.DecodeOption{CaseInsensitiveKeys: true})
+
+		err := gt.LoadConfig(strings.NewReader(data), "json")
+		customtests.OK(t, err)
+		customtests.Equals(t, Service{Host: "billing.local", Port: 8081}, gt.GetConfig())
+	})
+
+	t.Run("Test 3: later options replace registered ones", func(t *testing.T) {
+		registry := NewDefaultCodecRegister[Service]()
+		registry.RegisterCodecWithOptions("json", &json.Codec[Service]{}, &option.DecodeOption{CaseInsensitiveKeys: true}, nil)
+
+		gt := NewGathuk[Service]()
+		gt.SetCustomCodecRegistry(registry)
+		gt.SetDecodeOption("json", &option.DecodeOption{})
+
+		err := gt.LoadConfig(strings.NewReader(data), "json")
+		customtests.OK(t, err)
+		customtests.Equals(t, Service{}, gt.GetConfig())
+	})
+}
+
+// rawCodec is a stub codec storing the whole content in SimpleC.
+type rawCodec struct {
+	option.DefaultCodec[Simple]
+}
+
+func (rawCodec) Decode(buf []byte, val *Simple) error {
+	val.SimpleC = strings.TrimSpace(string(buf))
+	return nil
+}
+
+func (rawCodec) Encode(val Simple) ([]byte, error) {
+	return []byte(val.SimpleC), nil
+}
+
+// optionsCodec is a stub codec without Clone that reports in SimpleC whether
+// it decoded with AutomaticEnv.
+type optionsCodec struct {
+	option.DefaultCodec[Simple]
+	do *option.DecodeOption
+}
+
+func (c *optionsCodec) ApplyDecodeOption(do *option.DecodeOption) { c.do = do }
+
+func (c *optionsCodec) CheckDecodeOption() bool { return c.do != nil }
+
+func (c *optionsCodec) AppliedDecodeOption() *option.DecodeOption { return c.do }
+
+func (c *optionsCodec) Decode(buf []byte, val *Simple) error {
+	val.SimpleC = "file"
+	if c.do.AutomaticEnv {
+		val.SimpleC = "automatic"
+	}
+	return nil
+}
+
+func (c *optionsCodec) Encode(val Simple) ([]byte, error) {
+	return []byte(val.SimpleC), nil
+}
+
+func TestGathukRegisterCodec(t *testing.T) {
+	t.Run("Test 1: load with a registered codec", func(t *testing.T) {
+		gt := NewGathuk[Simple]().RegisterCodec("raw", &rawCodec{})
+
+		err := gt.LoadConfig(strings.NewReader("hello\n"), "raw")
+		customtests.OK(t, err)
+		customtests.Equals(t, Simple{SimpleC: "hello"}, gt.GetConfig())
+
+		// built-in formats are still available
+		err = gt.LoadConfig(strings.NewReader("SIMPLE_E=3"), "env")
+		customtests.OK(t, err)
+		customtests.Equals(t, Simple{SimpleC: "hello", SimpleE: 3}, gt.GetConfig())
+	})
+}
+
+func TestGathukLoadFromEnv(t *testing.T) {
+	t.Run("Test 1: populate from environment variables only", func(t *testing.T) {
+		t.Setenv("SIMPLE_E", "42")
+		t.Setenv("DEBUG_C", "true")
+		t.Setenv("DB_USER", "envuser")
+		t.Setenv("DB_SERVER_PORT", "5432")
+		t.Setenv("DB_POLING_MAX_POOL", "10")
+
+		gt := NewGathuk[Simple2]()
+		err := gt.LoadFromEnv()
+		customtests.OK(t, err)
+
+		got := gt.GetConfig()
+		customtests.Equals(t, 42, got.Simplee)
+		customtests.Equals(t, true, got.Debug)
+		customtests.Equals(t, Database{User: "envuser", Server: "5432", PoolingMax: 10}, got.Database)
+	})
+
+	t.Run("Test 2: environment overrides loaded files", func(t *testing.T) {
+		t.Setenv("SIMPLE_E", "42")
+
+		gt := NewGathuk[Simple]()
+		customtests.OK(t, gt.LoadConfigFiles(EXAMPLE_ENV_FILE))
+		customtests.OK(t, gt.LoadFromEnv())
+		customtests.Equals(t, Simple{SimpleC: "hore", SimpleE: 42}, gt.GetConfig())
+	})
+
+	t.Run("Test 3: invalid value", func(t *testing.T) {
+		t.Setenv("SIMPLE_E", "forty-two")
+
+		gt := NewGathuk[Simple]()
+		err := gt.LoadFromEnv()
+		customtests.Assert(t, err != nil, "expected conversion error")
+	})
+
+	t.Run("Test 4: options set for env apply", func(t *testing.T) {
+		t.Setenv("APP_SIMPLE_E", "7")
+		t.Setenv("SIMPLE_E", "42")
+
+		gt := NewGathuk[Simple]()
+		gt.SetDecodeOption("env", &option.DecodeOption{KeyPrefix: "APP"})
+		customtests.OK(t, gt.LoadFromEnv())
+		customtests.Equals(t, 7, gt.GetConfig().SimpleE)
+
+		dec, err := gt.CodecRegistry.Decoder("env")
+		customtests.OK(t, err)
+		customtests.Assert(t, !dec.(interface{ AppliedDecodeOption() *option.DecodeOption }).AppliedDecodeOption().AutomaticEnv,
+			"LoadFromEnv changed the options of the registered codec")
+	})
+
+	t.Run("Test 5: options of a codec without Clone are restored", func(t *testing.T) {
+		cdc := &optionsCodec{}
+		gt := NewGathuk[Simple]().RegisterCodec("env", cdc)
+		gt.SetDecodeOption("env", &option.DecodeOption{KeyPrefix: "APP", PreferFileOverEnv: true})
+
+		customtests.OK(t, gt.LoadFromEnv())
+		customtests.Equals(t, "automatic", gt.GetConfig().SimpleC)
+		customtests.Equals(t, option.DecodeOption{KeyPrefix: "APP", PreferFileOverEnv: true}, *cdc.do)
+
+		customtests.OK(t, gt.LoadConfigString("", "env"))
+		customtests.Equals(t, "file", gt.GetConfig().SimpleC)
+	})
+}
+
+func TestGathukOptionalConfigFiles(t *testing.T) {
+	missing := "./example/dotenv/.missing.env"
+
+	t.Run("Test 1: missing optional base file is skipped", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+		gt.SetConfigFilesOptional(missing)
+
+		err := gt.LoadConfigFiles(EXAMPLE_ENV_FILE)
+		customtests.OK(t, err)
+		customtests.Equals(t, "hore", gt.GetConfig().SimpleC)
+	})
+
+	t.Run("Test 2: present optional base file is loaded", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+		gt.SetConfigFilesOptional(missing, EXAMPLE_ENV_FILE)
+
+		err := gt.LoadConfigFiles()
+		customtests.OK(t, err)
+		customtests.Equals(t, 2, gt.GetConfig().SimpleE)
+	})
+
+	t.Run("Test 3: extra files stay required", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+		gt.SetConfigFilesOptional(EXAMPLE_ENV_FILE)
+
+		err := gt.LoadConfigFiles(missing)
+		customtests.Assert(t, errors.Is(err, os.ErrNotExist), "expected not exist error, got %v", err)
+	})
+
+	t.Run("Test 4: required base file", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+		gt.SetConfigFiles(missing)
+
+		err := gt.LoadConfigFiles(EXAMPLE_ENV_FILE)
+		customtests.Assert(t, errors.Is(err, os.ErrNotExist), "expected not exist error, got %v", err)
+	})
+
+	t.Run("Test 5: all optional base files missing", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+		gt.SetConfigFilesOptional(missing, "./example/dotenv/*.missing")
+
+		err := gt.LoadConfigFiles()
+		customtests.OK(t, err)
+		customtests.Equals(t, Simple{}, gt.GetConfig())
+	})
+}
+
+func TestGathukLoadOptionalConfigFiles(t *testing.T) {
+	t.Run("Test 1: present file loads, missing file is skipped", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		err := gt.LoadOptionalConfigFiles(EXAMPLE_ENV_FILE, "./example/dotenv/.local.env")
+		customtests.OK(t, err)
+		customtests.Equals(t, Simple{SimpleC: "hore", SimpleE: 2}, gt.GetConfig())
+	})
+
+	t.Run("Test 2: no file present", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		err := gt.LoadOptionalConfigFiles("./example/dotenv/.local.env")
+		customtests.OK(t, err)
+		customtests.Equals(t, Simple{}, gt.GetConfig())
+	})
+
+	t.Run("Test 3: existing file with an unsupported format still fails", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		err := gt.LoadOptionalConfigFiles(EXAMPLE_WEIRD_file)
+		customtests.Assert(t, err != nil, "expected unsupported format error")
+	})
+
+	t.Run("Test 4: existing file that fails to parse still fails", func(t *testing.T) {
+		broken := t.TempDir() + "/local.json"
+		customtests.OK(t, os.WriteFile(broken, []byte(`{"simple_c": `), 0o644))
+
+		gt := NewGathuk[Simple]()
+		err := gt.LoadOptionalConfigFiles(broken)
+		customtests.Assert(t, err != nil, "expected parse error")
+	})
+}
+
+func TestGathukLoadUnsupportedFormat(t *testing.T) {
+	t.Run("Test 1: error names the file and the format", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		err := gt.LoadConfigFiles(EXAMPLE_ENV_FILE, EXAMPLE_WEIRD_file)
+		customtests.Assert(t, err != nil, "expected error for unsupported format")
+		customtests.Assert(t, strings.Contains(err.Error(), EXAMPLE_WEIRD_file), "error does not name the file: %v", err)
+		customtests.Assert(t, strings.Contains(err.Error(), `"weird"`), "error does not name the format: %v", err)
+
+		// nothing is merged when a later file has an unsupported format
+		customtests.Equals(t, Simple{}, gt.GetConfig())
+	})
+}
+
+func TestGathukLoadMalformedFile(t *testing.T) {
+	t.Run("Test 1: error names the failing file", func(t *testing.T) {
+		dir := t.TempDir()
+		first := filepath.Join(dir, "base.json")
+		second := filepath.Join(dir, "override.json")
+		customtests.OK(t, os.WriteFile(first, []byte(`{"simple_c": "base"}`), 0o644))
+		customtests.OK(t, os.WriteFile(second, []byte(`{"simple_c": }`), 0o644))
+
+		gt := NewGathuk[Simple]()
+		err := gt.LoadConfigFiles(first, second)
+		customtests.Assert(t, err != nil, "expected error for malformed file")
+		customtests.Assert(t, strings.Contains(err.Error(), `load "`+second+`" (json)`), "error does not name the file: %v", err)
+		customtests.Assert(t, !strings.Contains(err.Error(), first), "error names the wrong file: %v", err)
+	})
+}
+
+func TestGathukAppendConfig(t *testing.T) {
+	t.Run("Test 1: overlay merged over base", func(t *testing.T) {
+		gt := NewGathuk[Simple2]()
+		err := gt.LoadConfig(strings.NewReader(`{"simple_e": 1, "debug_c": true, "db": {"user": "app", "poling_max_pool": 10}}`), "json")
+		customtests.OK(t, err)
+
+		err = gt.AppendConfig(strings.NewReader(`{"simple_e": 2, "db": {"poling_max_pool": 20}}`), "json")
+		customtests.OK(t, err)
+		customtests.Equals(t, Simple2{
+			Simplee:  2,
+			Debug:    true,
+			Database: Database{User: "app", PoolingMax: 20},
+		}, gt.GetConfig())
+	})
+
+	t.Run("Test 2: zero values do not clear, even with ForceOverride", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+		gt.ForceOverride = true
+		err := gt.LoadConfig(strings.NewReader("SIMPLE_C=base\nSIMPLE_E=1"), "env")
+		customtests.OK(t, err)
+
+		err = gt.AppendConfig(strings.NewReader("SIMPLE_E=5"), "env")
+		customtests.OK(t, err)
+		customtests.Equals(t, Simple{SimpleC: "base", SimpleE: 5}, gt.GetConfig())
+	})
+
+	t.Run("Test 3: map keys are layered", func(t *testing.T) {
+		gt := NewGathuk[map[string]any]()
+		customtests.OK(t, gt.LoadConfigString(`{"a": 1, "b": 2}`, "json"))
+
+		err := gt.AppendConfig(strings.NewReader(`{"b": 3}`), "json")
+		customtests.OK(t, err)
+		customtests.Equals(t, map[string]any{"a": int64(1), "b": int64(3)}, gt.GetConfig())
+	})
+
+	t.Run("Test 4: slices are concatenated", func(t *testing.T) {
+		gt := NewGathuk[[]int]()
+		customtests.OK(t, gt.LoadConfigString(`[1, 2]`, "json"))
+
+		err := gt.AppendConfig(strings.NewReader(`[3]`), "json")
+		customtests.OK(t, err)
+		customtests.Equals(t, []int{1, 2, 3}, gt.GetConfig())
+	})
+
+	t.Run("Test 5: pointer to struct fields are merged", func(t *testing.T) {
+		type Server struct {
+			Host string `config:"host"`
+			Port int    `config:"port"`
+		}
+		type Config struct {
+			DB *Server `config:"db"`
+		}
+
+		gt := NewGathuk[Config]()
+		customtests.OK(t, gt.LoadConfigString(`{"db": {"host": "localhost", "port": 1}}`, "json"))
+
+		err := gt.AppendConfig(strings.NewReader(`{"db": {"port": 2}}`), "json")
+		customtests.OK(t, err)
+		customtests.Equals(t, Server{Host: "localhost", Port: 2}, *gt.GetConfig().DB)
+	})
+
+	t.Run("Test 6: overlay turns off a default true bool", func(t *testing.T) {
+		type Flags struct {
+			On   bool   `config:"on" default:"true"`
+			Name string `config:"name"`
+		}
+
+		gt := NewGathuk[Flags]()
+		customtests.OK(t, gt.LoadConfigString(`{"name": "app"}`, "json"))
+		customtests.Equals(t, true, gt.GetConfig().On)
+
+		err := gt.AppendConfig(strings.NewReader(`{"on": false}`), "json")
+		customtests.OK(t, err)
+		customtests.Equals(t, Flags{On: false, Name: "app"}, gt.GetConfig())
+	})
+
+	t.Run("Test 7: slice fields set by the overlay are replaced", func(t *testing.T) {
+		type Config struct {
+			Tags []string `config:"tags"`
+			Name string   `config:"name"`
+		}
+
+		gt := NewGathuk[Config]()
+		customtests.OK(t, gt.LoadConfigString("TAGS=a,b\nNAME=app", "env"))
+
+		customtests.OK(t, gt.AppendConfig(strings.NewReader("TAGS=c"), "env"))
+		customtests.Equals(t, Config{Tags: []string{"c"}, Name: "app"}, gt.GetConfig())
+
+		customtests.OK(t, gt.AppendConfig(strings.NewReader("NAME=api"), "env"))
+		customtests.Equals(t, Config{Tags: []string{"c"}, Name: "api"}, gt.GetConfig())
+	})
+}
+
+type Item struct {
+	ID   string `config:"id"`
+	Name string `config:"name"`
+}
+
+func TestGathukTopLevelArray(t *testing.T) {
+	t.Run("Test 1: json array into a slice", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "items.json")
+		customtests.OK(t, os.WriteFile(path, []byte(`[{"id": "1", "name": "api"}, {"id": "2", "name": "worker"}]`), 0o644))
+
+		gt := NewGathuk[[]Item]()
+		err := gt.LoadConfigFiles(path)
+		customtests.OK(t, err)
+		customtests.Equals(t, []Item{{ID: "1", Name: "api"}, {ID: "2", Name: "worker"}}, gt.GetConfig())
+	})
+
+	t.Run("Test 2: json array into a struct", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "items.json")
+		customtests.OK(t, os.WriteFile(path, []byte(`[{"id": "1"}]`), 0o644))
+
+		gt := NewGathuk[Item]()
+		err := gt.LoadConfigFiles(path)
+		customtests.Assert(t, err != nil, "expected error for array into struct")
+	})
+
+	t.Run("Test 3: arrays of several files are concatenated", func(t *testing.T) {
+		dir := t.TempDir()
+		first := filepath.Join(dir, "a.json")
+		second := filepath.Join(dir, "b.json")
+		customtests.OK(t, os.WriteFile(first, []byte(`[{"id": "1"}, {"id": "2"}]`), 0o644))
+		customtests.OK(t, os.WriteFile(second, []byte(`[{"id": "3"}]`), 0o644))
+
+		gt := NewGathuk[[]Item]()
+		err := gt.LoadConfigFiles(first, second)
+		customtests.OK(t, err)
+		customtests.Equals(t, 3, len(gt.GetConfig()))
+		customtests.Equals(t, []Item{{ID: "1"}, {ID: "2"}, {ID: "3"}}, gt.GetConfig())
+	})
+
+	t.Run("Test 4: ForceOverride keeps the last array", func(t *testing.T) {
+		dir := t.TempDir()
+		first := filepath.Join(dir, "a.json")
+		second := filepath.Join(dir, "b.json")
+		customtests.OK(t, os.WriteFile(first, []byte(`[{"id": "1"}, {"id": "2"}]`), 0o644))
+		customtests.OK(t, os.WriteFile(second, []byte(`[{"id": "3"}]`), 0o644))
+
+		gt := NewGathuk[[]Item]()
+		gt.ForceOverride = true
+		customtests.OK(t, gt.LoadConfigFiles(first, second))
+		customtests.Equals(t, []Item{{ID: "3"}}, gt.GetConfig())
+	})
+}
+
+type AnyFields struct {
+	Port  any `config:"port"`
+	Ratio any `config:"ratio"`
+	Debug any `config:"debug"`
+	Name  any `config:"name"`
+}
+
+func TestGathukAnyFieldTypes(t *testing.T) {
+	t.Run("Test 1: same dynamic types from env and json", func(t *testing.T) {
+		fromEnv := NewGathuk[AnyFields]()
+		customtests.OK(t, fromEnv.LoadConfig(strings.NewReader("PORT=8080\nRATIO=0.5\nDEBUG=true\nNAME=api"), "env"))
+
+		fromJSON := NewGathuk[AnyFields]()
+		customtests.OK(t, fromJSON.LoadConfig(strings.NewReader(`{"port": 8080, "ratio": 0.5, "debug": true, "name": "api"}`), "json"))
+
+		want := AnyFields{Port: int64(8080), Ratio: 0.5, Debug: true, Name: "api"}
+		customtests.Equals(t, want, fromEnv.GetConfig())
+		customtests.Equals(t, want, fromJSON.GetConfig())
+	})
+
+	t.Run("Test 2: max uint64 from env", func(t *testing.T) {
+		gt := NewGathuk[AnyFields]()
+		customtests.OK(t, gt.LoadConfigString("PORT=18446744073709551615", "env"))
+
+		customtests.Equals(t, any(uint64(math.MaxUint64)), gt.GetConfig().Port)
+	})
+
+	t.Run("Test 3: same large integer from env and json", func(t *testing.T) {
+		fromEnv := NewGathuk[AnyFields]()
+		customtests.OK(t, fromEnv.LoadConfigString("PORT=18446744073709551615", "env"))
+
+		fromJSON := NewGathuk[AnyFields]()
+		customtests.OK(t, fromJSON.LoadConfigString(`{"port": 18446744073709551615}`, "json"))
+
+		want := AnyFields{Port: uint64(math.MaxUint64)}
+		customtests.Equals(t, want, fromEnv.GetConfig())
+		customtests.Equals(t, want, fromJSON.GetConfig())
+	})
+}
+
+func TestGathukLoadContext(t *testing.T) {
+	t.Run("Test 1: cancelled context stops a blocking reader", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		pr, pw := io.Pipe()
+		defer pw.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+		}()
+
+		err := gt.LoadConfigContext(ctx, pr, "env")
+		customtests.Assert(t, errors.Is(err, context.Canceled), "expected context.Canceled, got %v", err)
+		customtests.Equals(t, Simple{}, gt.GetConfig())
+	})
+
+	t.Run("Test 2: deadline stops a blocking reader", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		pr, pw := io.Pipe()
+		defer pw.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := gt.LoadConfigContext(ctx, pr, "env")
+		customtests.Assert(t, errors.Is(err, context.DeadlineExceeded), "expected context.DeadlineExceeded, got %v", err)
+	})
+
+	t.Run("Test 3: cancelled context between files", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := gt.LoadConfigFilesContext(ctx, EXAMPLE_ENV_FILE)
+		customtests.Assert(t, errors.Is(err, context.Canceled), "expected context.Canceled, got %v", err)
+		customtests.Equals(t, Simple{}, gt.GetConfig())
+	})
+
+	t.Run("Test 4: live context loads normally", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		err := gt.LoadConfigContext(ctx, strings.NewReader("SIMPLE_C=ctx"), "env")
+		customtests.OK(t, err)
+		customtests.Equals(t, "ctx", gt.GetConfig().SimpleC)
+	})
+}
+
+func TestGathukSetLogger(t *testing.T) {
+	t.Run("Test 1: messages route to the custom logger", func(t *testing.T) {
+		var buf bytes.Buffer
+		gt := NewGathuk[Simple]().SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+
+		func() {
+			defer func() { _ = recover() }()
+			gt.SetDecodeOption("weird", &option.DecodeOption{})
+		}()
+
+		customtests.Assert(t, strings.Contains(buf.String(), "decoder not found"), "expected log message, got %q", buf.String())
+	})
+
+	t.Run("Test 2: nil logger silences logging", func(t *testing.T) {
+		gt := NewGathuk[Simple]().SetLogger(nil)
+
+		func() {
+			defer func() { _ = recover() }()
+			gt.SetDecodeOption("weird", &option.DecodeOption{})
+		}()
+
+		customtests.Assert(t, gt.logger != nil, "expected a no-op logger")
+	})
+}
+
+func TestGathukReset(t *testing.T) {
+	t.Run("Test 1: reset clears loaded config", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		err := gt.LoadConfigFiles(EXAMPLE_ENV_FILE)
+		customtests.OK(t, err)
+		customtests.Equals(t, "hore", gt.GetConfig().SimpleC)
+
+		gt.Reset()
+		customtests.Equals(t, Simple{}, gt.GetConfig())
+
+		err = gt.LoadConfigFiles(EXAMPLE_CONF_D_dir + "/20-override.env")
+		customtests.OK(t, err)
+		customtests.Equals(t, Simple{SimpleE: 20}, gt.GetConfig())
+	})
 }
 
 func TestGathukWrite(t *testing.T) {
@@ -221,6 +932,31 @@ func TestGathukWrite(t *testing.T) {
 		})
 		customtests.OK(t, err)
 	})
+
+	t.Run("Test 2: env header is written first", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+		gt.SetEncodeOption("env", &option.EncodeOption{Header: "Generated by gathuk at 2024-01-02T15:04:05Z; do not edit"})
+
+		var buf bytes.Buffer
+		err := gt.WriteConfig(&buf, "env", Simple{SimpleC: "hore"})
+		customtests.OK(t, err)
+
+		first, _, _ := strings.Cut(buf.String(), "\n")
+		customtests.Equals(t, "# Generated by gathuk at 2024-01-02T15:04:05Z; do not edit", first)
+
+		customtests.OK(t, gt.LoadConfig(&buf, "env"))
+		customtests.Equals(t, "hore", gt.GetConfig().SimpleC)
+	})
+
+	t.Run("Test 3: json rejects a header", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+		gt.SetEncodeOption("json", &option.EncodeOption{Header: "do not edit"})
+
+		var buf bytes.Buffer
+		err := gt.WriteConfig(&buf, "json", Simple{SimpleC: "gore"})
+		customtests.Assert(t, err != nil, "expected error for a header in JSON output")
+		customtests.Equals(t, 0, buf.Len())
+	})
 }
 
 func BenchmarkGathuk(b *testing.B) {
@@ -269,3 +1005,48 @@ func BenchmarkGathuk(b *testing.B) {
 		}
 	})
 }
+
+func TestGathukMixedFormatMerge(t *testing.T) {
+	type Pool struct {
+		Host     string
+		MaxConns int `json:"maxConns"`
+		Port     int
+	}
+	type Config struct {
+		Name     string
+		LogLevel string         `config:"log_level"`
+		Database Pool           `config:"db"`
+		Tags     []string       `config:"tags"`
+		Limits   map[string]int `config:"limits"`
+	}
+
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.json")
+	override := filepath.Join(dir, "override.env")
+	customtests.OK(t, os.WriteFile(base, []byte(`{"name": "api", "log_level": "info", "db": {"host": "db.local", "maxConns": 5, "port": 5432}, "tags": ["a"], "limits": {"cpu": 1}}`), 0o644))
+	customtests.OK(t, os.WriteFile(override, []byte("LOG_LEVEL=debug\nDB_HOST=replica.local\nDB_MAX_CONNS=20\nTAGS=b,c\nLIMITS_MEMORY=512"), 0o644))
+
+	t.Run("Test 1: env overrides json field by field", func(t *testing.T) {
+		gt := NewGathuk[Config]()
+		customtests.OK(t, gt.LoadConfigFiles(base, override))
+
+		want := Config{
+			Name:     "api",
+			LogLevel: "debug",
+			Database: Pool{Host: "replica.local", MaxConns: 20, Port: 5432},
+			Tags:     []string{"b", "c"},
+			Limits:   map[string]int{"MEMORY": 512},
+		}
+		customtests.Equals(t, want, gt.GetConfig())
+	})
+
+	t.Run("Test 2: json overrides env the same way", func(t *testing.T) {
+		gt := NewGathuk[Config]()
+		customtests.OK(t, gt.LoadConfigFiles(override, base))
+
+		got := gt.GetConfig()
+		customtests.Equals(t, "info", got.LogLevel)
+		customtests.Equals(t, Pool{Host: "db.local", MaxConns: 5, Port: 5432}, got.Database)
+		customtests.Equals(t, map[string]int{"cpu": 1}, got.Limits)
+	})
+}
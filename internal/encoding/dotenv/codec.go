@@ -6,10 +6,14 @@
 // .env file format:
 //   - Each line contains a key-value pair: KEY=value
 //   - Lines starting with # are comments and are ignored
+//   - Inline comments start at a # preceded by whitespace
 //   - Empty lines are ignored
 //   - Keys are case-sensitive
+//   - A leading shell "export " keyword is ignored
 //   - Values are read as-is until end of line or comment
 //
+// The comment markers can be changed with DecodeOption.CommentPrefixes.
+//
 // Example .env file:
 //
 //	# Database configuration
@@ -26,6 +30,9 @@
 //   - int, int64: Integer values
 //   - float64: Floating-point values
 //   - bool: Boolean values (true/false)
+//   - time.Duration: Duration strings (1m30s)
+//   - time.Time: RFC 3339 timestamps
+//   - slices: Comma-separated lists of any of the above (1s,2s,4s)
 //
 // Nested structs are supported using the `nested` tag to define prefixes:
 //
@@ -39,16 +46,23 @@ package dotenv
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	utility "github.com/ahyalfan/gathuk/internal/utils"
 	"github.com/ahyalfan/gathuk/option"
 	"github.com/ahyalfan/gathuk/shared"
 )
 
+// defaultCommentPrefixes are the comment markers used when DecodeOption does
+// not configure any.
+var defaultCommentPrefixes = []string{"#"}
+
 // Codec implements the option.Codec interface for .env file format.
 //
 // It provides both encoding (struct to .env) and decoding (.env to struct)
@@ -77,8 +91,28 @@ type Codec[T any] struct {
 	eo *option.EncodeOption
 
 	// temp is a temporary map used during encoding/decoding to store
-	// key-value pairs before converting them to/from the struct
+	// key-value pairs before converting them to/from the struct. It is
+	// recreated at the start of every Encode and Decode call
 	temp map[string][]byte
+
+	// fileKeys holds the keys read from the decoded content itself, as
+	// opposed to keys merged from the OS environment
+	fileKeys map[string]struct{}
+	// order holds the keys of fileKeys in the order they first appear
+	order []string
+	// repeated holds every value of the keys listed more than once, in
+	// order, when DecodeOption.RepeatedKeysAsSlice is set
+	repeated map[string][][]byte
+	// used holds the keys assigned to a declared field during scanning,
+	// so the remaining ones can be collected by a catch-all field
+	used map[string]struct{}
+
+	// src is the content passed to the last Decode call, kept so comments
+	// can be extracted on demand by Comments
+	src []byte
+
+	// encoded holds the keys of temp in output order during Encode
+	encoded []string
 }
 
 // ApplyEncodeOption sets the encode options for this codec.
@@ -134,11 +168,18 @@ func (c *Codec[T]) CheckEncodeOption() bool {
 //	// PORT=8080
 //	// HOST=localhost
 func (c *Codec[T]) Encode(val T) ([]byte, error) {
-	if c.temp == nil {
-		c.temp = make(map[string][]byte)
+	header := c.header()
+	if m, ok := any(val).(shared.OrderedMap); ok {
+		return append(header, encodeOrdered(m, c.eo)...), nil
 	}
 
-	c.flattenWithNestedPrefix(val)
+	// start from an empty map so keys of a previous call do not leak
+	c.temp = make(map[string][]byte)
+	c.encoded = nil
+
+	if err := c.flattenWithNestedPrefix(val); err != nil {
+		return nil, err
+	}
 	// var build strings.Builder
 	// for k, v := range c.temp {
 	// 	build.WriteString(k)
@@ -149,16 +190,65 @@ func (c *Codec[T]) Encode(val T) ([]byte, error) {
 	//
 	// return []byte(build.String()), nil
 
-	var build []byte
-	for k, v := range c.temp {
+	build := header
+	for _, k := range c.encoded {
 		build = append(build, []byte(k)...)
 		build = append(build, '=')
-		build = append(build, v...)
+		build = append(build, formatValue(c.temp[k])...)
 		build = append(build, '\n')
 	}
 	return build, nil
 }
 
+// put stores an encoded value, remembering the position of new keys so
+// Encode writes them in field order.
+func (c *Codec[T]) put(key string, value []byte) {
+	if _, ok := c.temp[key]; !ok {
+		c.encoded = append(c.encoded, key)
+	}
+	c.temp[key] = value
+}
+
+// header returns EncodeOption.Header as comment lines, or nil when no header
+// is set. Each line of the header is prefixed with "# ".
+func (c *Codec[T]) header() []byte {
+	if c.eo == nil || c.eo.Header == "" {
+		return nil
+	}
+
+	var build []byte
+	for line := range strings.SplitSeq(strings.TrimRight(c.eo.Header, "\n"), "\n") {
+		build = append(build, '#')
+		if line != "" {
+			build = append(build, ' ')
+			build = append(build, line...)
+		}
+		build = append(build, '\n')
+	}
+	return build
+}
+
+// encodeOrdered encodes an OrderedMap as KEY=value lines in its key order.
+//
+// Parameters:
+//   - m: The ordered map to encode
+//   - eo: The encode options controlling value formatting, may be nil
+//
+// Returns:
+//   - []byte: The encoded .env content
+func encodeOrdered(m shared.OrderedMap, eo *option.EncodeOption) []byte {
+	var build []byte
+	for _, kv := range m {
+		build = append(build, kv.Key...)
+		build = append(build, '=')
+		if kv.Value != nil {
+			build = append(build, formatValue(parseToBytes(reflect.ValueOf(kv.Value), eo))...)
+		}
+		build = append(build, '\n')
+	}
+	return build
+}
+
 // ApplyDecodeOption sets the decode options for this codec.
 //
 // These options control how the codec behaves when decoding .env files to structs,
@@ -186,6 +276,24 @@ func (c *Codec[T]) CheckDecodeOption() bool {
 	return c.do != nil
 }
 
+// AppliedDecodeOption returns the decode options applied to this codec, or nil
+// if none have been set.
+func (c *Codec[T]) AppliedDecodeOption() *option.DecodeOption {
+	return c.do
+}
+
+// Clone returns a new codec carrying the same decode and encode options as c.
+//
+// The clone starts without the keys, values and source kept from earlier
+// calls, so it can decode while c is in use elsewhere, and options applied
+// to it never reach c.
+//
+// Returns:
+//   - option.Codec[T]: The new codec
+func (c *Codec[T]) Clone() option.Codec[T] {
+	return &Codec[T]{do: c.do, eo: c.eo}
+}
+
 // Decode parses .env file content and populates a configuration struct.
 //
 // The decoding process:
@@ -197,9 +305,15 @@ func (c *Codec[T]) CheckDecodeOption() bool {
 //  6. Optionally reads from environment variables based on DecodeOption
 //  7. Converts string values to appropriate Go types
 //
+// Flat structs of scalar fields are decoded straight into their fields,
+// skipping the intermediate key-value map, when the options allow it (see
+// flatFieldsFor). The result is the same as with the generic path.
+//
 // Supported line formats:
 //   - KEY=value          # Standard format
 //   - KEY=value # comment # With inline comment
+//   - KEY=#value         # Marker without leading whitespace is part of the value
+//   - export KEY=value   # Shell export prefix is ignored
 //   - # comment          # Comment line (ignored)
 //   - # Empty line (ignored)
 //
@@ -220,43 +334,104 @@ func (c *Codec[T]) CheckDecodeOption() bool {
 //	data := []byte("PORT=8080\nHOST=localhost")
 //	config, err := codec.Decode(data)
 func (c *Codec[T]) Decode(buf []byte, val *T) error {
-	if c.temp == nil {
-		c.temp = make(map[string][]byte)
-	}
+	c.src = buf
 
-	lines := bytes.SplitSeq(buf, []byte{'\n'})
+	do := c.decodeOption()
+	if fields, ok := flatFieldsFor(reflect.TypeOf(val).Elem(), do); ok {
+		// no key of a previous call may leak into later scans
+		c.temp, c.fileKeys, c.used, c.repeated, c.order = nil, nil, nil, nil, nil
+		return decodeFlat(buf, val, fields, do)
+	}
+	return c.decode(buf, val)
+}
 
-	for line := range lines {
+// DecodeInto is like Decode, but populates dst, which may point to any type,
+// not just T. Keys are resolved from the type of dst with the same decode
+// options, so a codec for a whole configuration can fill a single section.
+//
+// Parameters:
+//   - buf: Byte slice containing .env file content
+//   - dst: Non-nil pointer to the value to populate
+//
+// Returns an error if dst is not a non-nil pointer or decoding fails.
+//
+// Example:
+//
+//	var db Database
+//	err := codec.DecodeInto([]byte("DB_HOST=localhost"), &db)
+func (c *Codec[T]) DecodeInto(buf []byte, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("decode destination must be a non-nil pointer, got %T", dst)
+	}
+	c.src = buf
+	return c.decode(buf, dst)
+}
 
-		line = bytes.TrimSpace(line)
-		escape := bytes.IndexByte(line, '#')
-		if escape != -1 {
-			line = line[:escape]
-		}
+// decode is the generic decoding path used by Decode and DecodeInto. It
+// collects every key-value pair into the temp map, optionally merges the OS
+// environment, and then scans the map into the target value.
+//
+// Parameters:
+//   - buf: Byte slice containing .env file content
+//   - val: Pointer to the value to populate
+//
+// Returns:
+//   - error: An error if decoding fails
+func (c *Codec[T]) decode(buf []byte, val any) error {
+	// start from an empty map so keys of a previous call do not leak
+	c.temp = make(map[string][]byte)
+	c.fileKeys = make(map[string]struct{})
+	c.used = make(map[string]struct{})
+	c.repeated = nil
+	c.order = nil
 
-		bs := bytes.Split(line, []byte(" "))
+	do := c.decodeOption()
+	lines := splitLines(buf, do.CommentPrefixes)
 
-		if len(bs) < 1 {
-			continue
+	keys := 0
+	for line, err := range lines {
+		if err != nil {
+			return err
 		}
-		bs = bytes.Split(bs[0], []byte("="))
-
-		if len(bs) < 2 {
+		if err := checkStrict(line, do); err != nil {
+			return err
+		}
+		key, value, ok := parseLine(line, do)
+		if !ok {
 			continue
 		}
+		if err := checkValueLen(key, value, do.MaxValueLen); err != nil {
+			return err
+		}
+		keys++
+		if err := checkKeyCount(keys, do.MaxKeys); err != nil {
+			return err
+		}
+		value, err := resolveSecret(key, value, do)
+		if err != nil {
+			return err
+		}
 
-		c.temp[string(bs[0])] = bs[1]
+		if prev, ok := c.temp[string(key)]; ok && do.RepeatedKeysAsSlice {
+			c.addRepeated(string(key), prev, value)
+		}
+		c.temp[string(key)] = value
+		if _, ok := c.fileKeys[string(key)]; !ok {
+			c.fileKeys[string(key)] = struct{}{}
+			c.order = append(c.order, string(key))
+		}
 
-		if c.do.PersistToOSEnv {
-			err := os.Setenv(string(bs[0]), string(bs[1]))
+		if do.PersistToOSEnv {
+			err := os.Setenv(string(key), string(value))
 			if err != nil {
 				return nil
 			}
 		}
 	}
 
-	if c.do.AutomaticEnv {
-		if c.do.PreferFileOverEnv {
+	if do.AutomaticEnv {
+		if do.PreferFileOverEnv {
 			for _, e := range os.Environ() {
 				pair := strings.SplitN(e, "=", 2)
 				if _, ok := c.temp[pair[0]]; !ok {
@@ -267,6 +442,8 @@ func (c *Codec[T]) Decode(buf []byte, val *T) error {
 			for _, e := range os.Environ() {
 				pair := strings.SplitN(e, "=", 2)
 				c.temp[pair[0]] = []byte(pair[1])
+				// the environment replaces every value the file listed
+				delete(c.repeated, pair[0])
 			}
 		}
 	}
@@ -276,10 +453,259 @@ func (c *Codec[T]) Decode(buf []byte, val *T) error {
 	return err
 }
 
+// addRepeated records value as a further occurrence of key, whose value so
+// far is prev.
+func (c *Codec[T]) addRepeated(key string, prev, value []byte) {
+	if c.repeated == nil {
+		c.repeated = make(map[string][][]byte)
+	}
+	if _, ok := c.repeated[key]; !ok {
+		c.repeated[key] = [][]byte{prev}
+	}
+	c.repeated[key] = append(c.repeated[key], value)
+}
+
+// Reset clears the key-value pairs collected by previous Encode or Decode
+// calls, so the codec can be reused from a clean state.
+func (c *Codec[T]) Reset() {
+	clear(c.temp)
+	clear(c.fileKeys)
+	clear(c.used)
+	c.repeated = nil
+	c.order = nil
+	c.src = nil
+}
+
+// decodeOption returns the decode options applied to this codec, falling back
+// to the zero DecodeOption when none have been set.
+func (c *Codec[T]) decodeOption() *option.DecodeOption {
+	if c.do == nil {
+		return &option.DecodeOption{}
+	}
+	return c.do
+}
+
+// resolveSecret resolves a value holding a secret reference with
+// DecodeOption.SecretResolver, see shared.ResolveSecret.
+//
+// Parameters:
+//   - key: The key of the value, used in error messages
+//   - value: The value as read from the file
+//   - do: The decode options holding the resolver
+//
+// Returns:
+//   - []byte: The resolved value
+//   - error: An error if the resolver fails
+func resolveSecret(key, value []byte, do *option.DecodeOption) ([]byte, error) {
+	if do.SecretResolver == nil {
+		return value, nil
+	}
+
+	resolved, err := shared.ResolveSecret(string(value), do.SecretResolver)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	return []byte(resolved), nil
+}
+
+// parseLine extracts the key and value from a single .env line.
+//
+// Comments and a leading "export" keyword are removed first. Lines without
+// a KEY=value pair (blank lines, comment lines) are reported as not ok.
+// The line is split on the first "=" only, so the value may contain "=",
+// e.g. TOKEN=a=b=c or padded base64. Whitespace around the key is always
+// dropped; whitespace around the value is dropped unless
+// DecodeOption.TrimSpace is set to false. A double quoted value is returned
+// without its quotes and kept exactly as written.
+//
+// Parameters:
+//   - line: The raw line
+//   - do: The decode options controlling comment handling
+//
+// Returns:
+//   - key: The key of the pair
+//   - value: The value of the pair
+//   - ok: false if the line does not hold a pair
+func parseLine(line []byte, do *option.DecodeOption) (key, value []byte, ok bool) {
+	line = bytes.TrimRight(line, "\r")
+	line = stripComment(bytes.TrimLeft(line, " \t"), do.CommentPrefixes)
+	line = stripExport(line)
+
+	key, value, ok = bytes.Cut(line, []byte("="))
+	if !ok {
+		return nil, nil, false
+	}
+	key = bytes.TrimSpace(key)
+	if quoted, rest, ok := cutQuoted(bytes.TrimLeft(value, " \t")); ok && len(bytes.TrimSpace(rest)) == 0 {
+		return key, []byte(quoted), true
+	}
+	if trimSpace(do) {
+		value = bytes.TrimSpace(value)
+	}
+	return key, value, true
+}
+
+// checkStrict enforces DecodeOption.StrictEnv on a raw .env line. A value that
+// is not double quoted must not hold whitespace, "#" or "=", since these make
+// it ambiguous: PASSWORD=abc #def reads as "abc" followed by a comment. A
+// quoted value may only be followed by a comment, starting with one of the
+// configured CommentPrefixes.
+//
+// Parameters:
+//   - line: The raw line
+//   - do: The decode options
+//
+// Returns:
+//   - error: An error naming the key if the value needs quoting
+func checkStrict(line []byte, do *option.DecodeOption) error {
+	if !do.StrictEnv {
+		return nil
+	}
+	line = bytes.TrimRight(line, "\r")
+	if len(stripComment(bytes.TrimLeft(line, " \t"), do.CommentPrefixes)) == 0 {
+		return nil
+	}
+
+	key, value, ok := bytes.Cut(stripExport(bytes.TrimLeft(line, " \t")), []byte("="))
+	if !ok {
+		return nil
+	}
+	key = bytes.TrimSpace(key)
+	value = bytes.TrimSpace(value)
+	if _, rest, ok := cutQuoted(value); ok {
+		if rest = bytes.TrimLeft(rest, " \t"); len(rest) == 0 || hasCommentPrefix(rest, do.CommentPrefixes) {
+			return nil
+		}
+		return fmt.Errorf("strict env: unexpected %q after quoted value of %s", rest, key)
+	}
+	if i := bytes.IndexAny(value, " \t#="); i >= 0 {
+		return fmt.Errorf("strict env: unquoted value of %s contains %q, quote the value or move a comment to its own line", key, value[i])
+	}
+	return nil
+}
+
+// trimSpace reports whether values should be trimmed, which is the default
+// when DecodeOption.TrimSpace is not set.
+func trimSpace(do *option.DecodeOption) bool {
+	return do.TrimSpace == nil || *do.TrimSpace
+}
+
+// checkValueLen enforces DecodeOption.MaxValueLen on a single value.
+//
+// Parameters:
+//   - key: The key of the value, used in the error message
+//   - value: The value to check
+//   - maxLen: The maximum length in bytes; 0 means unlimited
+//
+// Returns:
+//   - error: An error if the value is longer than maxLen
+func checkValueLen(key, value []byte, maxLen int) error {
+	if maxLen > 0 && len(value) > maxLen {
+		return fmt.Errorf("value of %s exceeds maximum length of %d bytes (got %d)", key, maxLen, len(value))
+	}
+	return nil
+}
+
+// checkKeyCount enforces DecodeOption.MaxKeys while keys are being read.
+//
+// Parameters:
+//   - n: The number of keys read so far
+//   - maxKeys: The maximum number of keys; 0 means unlimited
+//
+// Returns:
+//   - error: An error if n exceeds maxKeys
+func checkKeyCount(n, maxKeys int) error {
+	if maxKeys > 0 && n > maxKeys {
+		return fmt.Errorf("input exceeds maximum of %d keys", maxKeys)
+	}
+	return nil
+}
+
+// stripComment removes a comment from a trimmed .env line.
+//
+// A line whose content starts with one of the comment prefixes is a full line
+// comment and yields an empty result. Otherwise a prefix only starts an inline
+// comment when it is preceded by whitespace, so values such as COLOR=#ff0000
+// are kept intact. A comment marker inside a double quoted value does not
+// start a comment. Whitespace in front of an inline comment is left in place
+// for the caller to trim.
+//
+// Parameters:
+//   - line: The line with leading whitespace already trimmed
+//   - prefixes: The comment markers to recognize, defaulting to "#" when empty
+//
+// Returns:
+//   - []byte: The line without its comment
+func stripComment(line []byte, prefixes []string) []byte {
+	if len(prefixes) == 0 {
+		prefixes = defaultCommentPrefixes
+	}
+
+	if hasCommentPrefix(line, prefixes) {
+		return nil
+	}
+
+	quoted := false
+	for i := 1; i < len(line); i++ {
+		switch {
+		case quoted && line[i] == '\\':
+			i++
+			continue
+		case line[i] == '"' && (quoted || bytes.HasSuffix(bytes.TrimRight(line[:i], " \t"), []byte("="))):
+			quoted = !quoted
+			continue
+		case quoted:
+			continue
+		}
+
+		if line[i-1] != ' ' && line[i-1] != '\t' {
+			continue
+		}
+		for _, prefix := range prefixes {
+			if prefix != "" && bytes.HasPrefix(line[i:], []byte(prefix)) {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+// hasCommentPrefix reports whether b starts with one of the comment prefixes,
+// defaulting to "#" when none are given.
+func hasCommentPrefix(b []byte, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		prefixes = defaultCommentPrefixes
+	}
+	for _, prefix := range prefixes {
+		if prefix != "" && bytes.HasPrefix(b, []byte(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// stripExport removes a leading shell "export" keyword from a trimmed .env
+// line, so files meant to be sourced by a shell can be decoded as well.
+//
+// Lines without the keyword are returned unchanged.
+//
+// Example:
+//
+//	stripExport([]byte("export DB_HOST=localhost")) // Returns: "DB_HOST=localhost"
+func stripExport(line []byte) []byte {
+	rest, ok := bytes.CutPrefix(line, []byte("export"))
+	if !ok || len(rest) == 0 || (rest[0] != ' ' && rest[0] != '\t') {
+		return line
+	}
+	return bytes.TrimLeft(rest, " \t")
+}
+
 // flattenWithNestedPrefix initiates the flattening process for encoding.
 //
 // This method prepares a struct for encoding by flattening nested structures
-// and converting field names to configuration keys.
+// and converting field names to configuration keys. A map with string keys,
+// such as map[string]any, is written as one upper case key per entry, in key
+// order.
 //
 // Parameters:
 //   - v: The configuration struct to flatten
@@ -291,10 +717,48 @@ func (c *Codec[T]) flattenWithNestedPrefix(v T) error {
 	if vt.Kind() == reflect.Ptr {
 		vt = vt.Elem()
 	}
-	parent := reflect.TypeOf(v)
-	c.flattenNestedWithNestedPrefix(parent, vt, "")
+	// a map with string keys is written as one key per entry
+	if vt.Kind() == reflect.Map && vt.Type().Key().Kind() == reflect.String {
+		c.flattenCatchAll(vt, "")
+		return nil
+	}
+	return c.flattenNestedWithNestedPrefix(0, vt, c.keyPrefix(vt))
+}
 
-	return nil
+// keyPrefix returns DecodeOption.KeyPrefix in upper case without its trailing
+// "_", used as the root prefix of the keys of a struct. Other root values,
+// such as maps, are not prefixed.
+func (c *Codec[T]) keyPrefix(root reflect.Value) string {
+	if root.Kind() != reflect.Struct {
+		return ""
+	}
+	return c.rootPrefix()
+}
+
+// rootPrefix returns DecodeOption.KeyPrefix in upper case without its
+// trailing "_".
+func (c *Codec[T]) rootPrefix() string {
+	return strings.ToUpper(strings.TrimSuffix(c.decodeOption().KeyPrefix, "_"))
+}
+
+// prefixSeparator returns the string joining prefix to the keys below it:
+// "_" after the KeyPrefix itself, so MYAPP_PORT keeps its documented form
+// whatever the separator, and separator() between nested segments.
+func (c *Codec[T]) prefixSeparator(prefix string) string {
+	if prefix != "" && prefix == c.rootPrefix() {
+		return "_"
+	}
+	return c.separator()
+}
+
+// separator returns the string joining the prefix of a nested struct to its
+// keys, DecodeOption.NestedSeparator or "_" by default. It is used when
+// decoding and encoding alike, so both sides agree on the keys.
+func (c *Codec[T]) separator() string {
+	if sep := c.decodeOption().NestedSeparator; sep != "" {
+		return sep
+	}
+	return "_"
 }
 
 // flattenNestedWithNestedPrefix recursively flattens a struct into key-value pairs
@@ -302,103 +766,269 @@ func (c *Codec[T]) flattenWithNestedPrefix(v T) error {
 //
 // This method processes each field of the struct:
 //   - For nested structs: Recursively processes with the appropriate prefix
+//   - For pointers to nested structs: Dereferences them, skipping nil pointers
 //   - For basic types: Converts to string and stores in temp map
+//   - For catch-all maps: Writes each entry back as its own key
+//   - For embedded structs without a tag: Promotes their fields to this level
 //   - Respects `config` and `nested` struct tags
+//   - Skips `secret` fields when EncodeOption.ExcludeSecrets is set
+//   - Writes `gz64` fields as gzip compressed, base64 encoded "gz64:" values
+//
+// Pointer cycles in the value, such as a.B.A == a, are followed at most
+// maxNestingDepth levels deep; deeper nesting is an error.
 //
 // Parameters:
-//   - parent: The parent type (used to prevent infinite recursion)
+//   - depth: The nesting level of v, 0 for the root
 //   - v: The reflect.Value of the struct to flatten
 //   - nestedPrefix: The prefix to prepend to field names
+//
+// Returns:
+//   - error: An error if the nesting is too deep
 func (c *Codec[T]) flattenNestedWithNestedPrefix(
-	parent reflect.Type, v reflect.Value, nestedPrefix string,
-) {
-	for i := 0; i < v.NumField(); i++ {
+	depth int, v reflect.Value, nestedPrefix string,
+) error {
+	if depth > maxNestingDepth {
+		return depthError(nestedPrefix)
+	}
+	excludeSecrets := c.eo != nil && c.eo.ExcludeSecrets
+	redactSecrets := c.eo != nil && c.eo.RedactSecrets
+
+	for _, i := range shared.FieldOrder(v.Type()) {
 		field := v.Field(i)
 		structField := v.Type().Field(i)
 
-		if structField.Type.Kind() == reflect.Struct && structField.Type != parent {
-			nestedName := structField.Tag.Get(string(shared.GetTagNestedName()))
-			if nestedName == "-" {
-				continue
-			}
-			if nestedName == "" {
-				nestedName = structField.Tag.Get(string(shared.GetTagName()))
-				if nestedName == "-" {
-					continue
+		if excludeSecrets && shared.IsSecret(structField) {
+			continue
+		}
+		if redactSecrets && shared.IsSecret(structField) {
+			if name := FieldKey(structField); name != "" {
+				if nestedPrefix != "" {
+					name = nestedPrefix + c.prefixSeparator(nestedPrefix) + name
 				}
+				c.put(strings.ToUpper(name), []byte(shared.Redacted))
 			}
-			if nestedName == "" {
-				nestedName = structField.Tag.Get("env")
-				if nestedName == "-" {
-					continue
-				}
+			continue
+		}
+
+		if shared.IsCatchAll(structField) {
+			c.flattenCatchAll(field, nestedPrefix)
+			continue
+		}
+
+		if isPromoted(structField) {
+			if err := c.flattenNestedWithNestedPrefix(depth+1, field, nestedPrefix); err != nil {
+				return err
 			}
+			continue
+		}
+
+		if isNestedStruct(structField.Type) {
+			nestedName := FieldKey(structField)
 			if nestedName == "" {
-				nestedName = utility.PascalToUpperSnakeCase(structField.Name)
+				continue
 			}
 			if nestedPrefix != "" {
-				nestedName = nestedPrefix + "_" + nestedName
+				nestedName = nestedPrefix + c.prefixSeparator(nestedPrefix) + nestedName
+			}
+			if err := c.flattenNestedWithNestedPrefix(depth+1, field, nestedName); err != nil {
+				return err
 			}
-			c.scanNestedWithNestedPrefix(parent, field, nestedName)
 			continue
 		}
 
-		var name string
-		name = structField.Tag.Get(string(shared.GetTagName()))
-		if name == "-" {
+		if isStructPtr(structField.Type) {
+			nestedName := FieldKey(structField)
+			if nestedName == "" || field.IsNil() {
+				continue
+			}
+			if nestedPrefix != "" {
+				nestedName = nestedPrefix + c.prefixSeparator(nestedPrefix) + nestedName
+			}
+			if err := c.flattenNestedWithNestedPrefix(depth+1, field.Elem(), nestedName); err != nil {
+				return err
+			}
 			continue
 		}
-		if name == "" {
-			name = structField.Tag.Get("env")
-			if name == "-" {
+
+		// map fields are written like a catch-all under their own key
+		if structField.Type.Kind() == reflect.Map && !isStructMap(structField.Type) {
+			nestedName := FieldKey(structField)
+			if nestedName == "" {
 				continue
 			}
+			if nestedPrefix != "" {
+				nestedName = nestedPrefix + c.prefixSeparator(nestedPrefix) + nestedName
+			}
+			c.flattenCatchAll(field, nestedName)
+			continue
 		}
 
+		name := FieldKey(structField)
 		if name == "" {
-			name = utility.PascalToUpperSnakeCase(structField.Name)
+			continue
 		}
 
 		if nestedPrefix != "" {
-			sub := nestedPrefix + "_"
+			sub := nestedPrefix + c.prefixSeparator(nestedPrefix)
 			name = sub + name
 		}
 		name = strings.ToUpper(name)
 
-		c.temp[name] = parseToBytes(field)
+		if shared.IsGz64(structField) {
+			c.put(name, []byte(shared.EncodeGz64(field.Bytes())))
+			continue
+		}
+		c.put(name, parseToBytes(field, c.eo))
+	}
+	return nil
+}
+
+// flattenCatchAll writes the entries of a catch-all map back as individual
+// keys under nestedPrefix. Keys of declared fields take precedence over
+// catch-all entries with the same name. It also writes map fields, with the
+// key of the field as nestedPrefix.
+//
+// Parameters:
+//   - field: The catch-all map value
+//   - nestedPrefix: The prefix of the struct holding the catch-all field
+func (c *Codec[T]) flattenCatchAll(field reflect.Value, nestedPrefix string) {
+	keys := field.MapKeys()
+	slices.SortFunc(keys, func(a, b reflect.Value) int {
+		return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+	})
+
+	for _, key := range keys {
+		name := fmt.Sprint(key.Interface())
+		if nestedPrefix != "" {
+			name = nestedPrefix + c.prefixSeparator(nestedPrefix) + name
+		}
+		name = strings.ToUpper(name)
+		if _, ok := c.temp[name]; ok {
+			continue
+		}
+		c.put(name, parseToBytes(field.MapIndex(key), c.eo))
 	}
 }
 
+// isPromoted reports whether the fields of an embedded struct field are
+// promoted to the parent level, i.e. the field has no `nested`, `config`,
+// `env` or `json` tag naming it.
+func isPromoted(field reflect.StructField) bool {
+	return shared.IsPromoted(field, string(shared.GetTagNestedName()), string(shared.GetTagName()), "env", "json")
+}
+
+// FieldKey returns the .env key segment a struct field maps to, without any
+// nested prefix.
+//
+// The key is resolved in this order:
+//  1. The `nested` tag (deprecated, struct and pointer-to-struct fields only)
+//  2. The `env` tag, so a field can use a different key in .env files than
+//     in other formats
+//  3. The `config` tag
+//  4. The `json` tag converted to UPPER_SNAKE_CASE, so structs shared with
+//     JSON loading need no extra tags
+//  5. The field name converted to UPPER_SNAKE_CASE
+//
+// Tag options after a comma (e.g. `config:"password,secret"`) are ignored.
+//
+// Parameters:
+//   - field: The struct field to resolve
+//
+// Returns:
+//   - string: The uppercased key, or "" if the field is excluded with "-"
+//
+// Example:
+//
+//	type Config struct {
+//	    Port     int    `config:"server_port"` // FieldKey: "SERVER_PORT"
+//	    LogLevel string                        // FieldKey: "LOG_LEVEL"
+//	    MaxConns int    `json:"maxConns"`      // FieldKey: "MAX_CONNS"
+//	    Addr     string `config:"addr" env:"SERVER_ADDR"` // FieldKey: "SERVER_ADDR"
+//	}
+func FieldKey(field reflect.StructField) string {
+	tags := []string{"env", string(shared.GetTagName())}
+	if isNestedStruct(field.Type) || isStructPtr(field.Type) {
+		tags = append([]string{string(shared.GetTagNestedName())}, tags...)
+	}
+
+	for _, tag := range tags {
+		value := field.Tag.Get(tag)
+		if value == "-" {
+			return ""
+		}
+		if name, _ := shared.ParseTag(value); name != "" {
+			return strings.ToUpper(name)
+		}
+	}
+
+	value := field.Tag.Get("json")
+	if value == "-" {
+		return ""
+	}
+	if name, _ := shared.ParseTag(value); name != "" {
+		return utility.PascalToUpperSnakeCase(name)
+	}
+	return utility.PascalToUpperSnakeCase(field.Name)
+}
+
 // parseToBytes converts a struct field value to its byte representation.
 //
 // This function is used during encoding to convert Go values to strings
 // that can be written to .env files.
 //
 // Supported types:
-//   - string: Direct conversion to bytes
+//   - string: Direct conversion to bytes; Encode quotes it when needed
 //   - int, int8, int16, int32, int64: Formatted as base-10 integer
 //   - uint, uint8, uint16, uint32, uint64: Formatted as base-10 unsigned integer
-//   - float32, float64: Formatted as floating-point number
+//   - float32, float64: Formatted as the shortest number that reads back to
+//     the same value at the width of the field, without an exponent, unless
+//     EncodeOption.FloatFormat or FloatPrecision say otherwise
+//   - pointers: The value pointed to; nil pointers give an empty value
 //   - bool: Formatted as "true" or "false"
+//   - time.Duration: Formatted as a duration string (e.g. "1m30s")
+//   - time.Time: Formatted as RFC 3339
+//   - slices, arrays: Elements joined with ","
+//
+// The value is only read, never modified, so every width written here is
+// read back by setValue.
 //
 // Parameters:
 //   - field: The reflect.Value of the field to convert
+//   - eo: The encode options controlling float formatting, may be nil
 //
 // Returns:
 //   - []byte: The byte representation of the field value, or nil for unsupported types
-func parseToBytes(field reflect.Value) []byte {
+func parseToBytes(field reflect.Value, eo *option.EncodeOption) []byte {
+	// only read the value: a nil pointer is written as an empty value
 	if field.Kind() == reflect.Ptr {
 		if field.IsNil() {
-			field.Set(reflect.New(field.Type().Elem()))
+			return nil
 		}
 		field = field.Elem()
 	}
 
+	switch field.Type() {
+	case durationType:
+		return []byte(time.Duration(field.Int()).String())
+	case timeType:
+		return []byte(field.Interface().(time.Time).Format(time.RFC3339))
+	}
+
 	// Basic kinds
 	switch field.Kind() {
 	case reflect.String:
 		return []byte(field.String())
 
+	case reflect.Slice, reflect.Array:
+		var build []byte
+		for i := 0; i < field.Len(); i++ {
+			if i > 0 {
+				build = append(build, ',')
+			}
+			build = append(build, parseToBytes(field.Index(i), eo)...)
+		}
+		return build
+
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		return []byte(strconv.FormatInt(field.Int(), 10))
 
@@ -406,10 +1036,18 @@ func parseToBytes(field reflect.Value) []byte {
 		return []byte(strconv.FormatUint(field.Uint(), 10))
 
 	case reflect.Float32, reflect.Float64:
-		return []byte(strconv.FormatFloat(field.Float(), 'f', -1, 64))
+		if eo == nil {
+			eo = &option.EncodeOption{}
+		}
+		return []byte(shared.FormatFloat(field.Float(), eo.FloatFormat, 'f', eo.FloatPrecision, field.Type().Bits()))
 
 	case reflect.Bool:
 		return []byte(strconv.FormatBool(field.Bool()))
+
+	case reflect.Interface:
+		if !field.IsNil() {
+			return parseToBytes(field.Elem(), eo)
+		}
 	}
 	return nil
 }
@@ -0,0 +1,98 @@
+// Package dotenv
+package dotenv
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/ahyalfan/gathuk/option"
+)
+
+// Comments returns the comment attached to each key of the content passed to
+// the last Decode call.
+//
+// A key's comment is its inline comment (DB_HOST=localhost # primary host) or,
+// when there is none, the block of full-line comments directly above it.
+// Lines of a block are joined with "\n"; a blank line ends a block.
+//
+// Returns:
+//   - map[string]string: Comment text keyed by .env key, without the
+//     comment markers. Keys without a comment are omitted
+//
+// Example:
+//
+//	// # Database host
+//	// DB_HOST=localhost
+//	// DB_PORT=5432 # default port
+//	codec.Comments() // map[DB_HOST:Database host DB_PORT:default port]
+func (c *Codec[T]) Comments() map[string]string {
+	return parseComments(c.src, c.decodeOption())
+}
+
+// parseComments collects the comment of every key in .env content.
+// See Codec.Comments for the rules.
+//
+// Parameters:
+//   - buf: Byte slice containing .env file content
+//   - do: The decode options controlling comment markers
+//
+// Returns:
+//   - map[string]string: Comment text keyed by .env key
+func parseComments(buf []byte, do *option.DecodeOption) map[string]string {
+	prefixes := do.CommentPrefixes
+	if len(prefixes) == 0 {
+		prefixes = defaultCommentPrefixes
+	}
+
+	comments := make(map[string]string)
+	var block []string
+
+	for line, err := range splitLines(buf, prefixes) {
+		if err != nil {
+			// Decode reports the unterminated quote
+			break
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			block = nil
+			continue
+		}
+
+		key, _, ok := parseLine(line, do)
+		if !ok {
+			if text, ok := cutCommentPrefix(line, prefixes); ok {
+				block = append(block, text)
+			}
+			continue
+		}
+
+		if inline := inlineComment(line, prefixes); inline != "" {
+			comments[string(key)] = inline
+		} else if len(block) > 0 {
+			comments[string(key)] = strings.Join(block, "\n")
+		}
+		block = nil
+	}
+	return comments
+}
+
+// cutCommentPrefix returns the text of a full-line comment without its marker.
+func cutCommentPrefix(line []byte, prefixes []string) (string, bool) {
+	for _, prefix := range prefixes {
+		if prefix != "" && bytes.HasPrefix(line, []byte(prefix)) {
+			return string(bytes.TrimSpace(line[len(prefix):])), true
+		}
+	}
+	return "", false
+}
+
+// inlineComment returns the text of the inline comment of a key line, or ""
+// if it has none. The comment is what stripComment removes from the line.
+func inlineComment(line []byte, prefixes []string) string {
+	content := stripComment(line, prefixes)
+	if len(content) == len(line) {
+		return ""
+	}
+	text, _ := cutCommentPrefix(bytes.TrimSpace(line[len(content):]), prefixes)
+	return text
+}
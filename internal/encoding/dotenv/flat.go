@@ -0,0 +1,138 @@
+// Package dotenv
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/ahyalfan/gathuk/option"
+	"github.com/ahyalfan/gathuk/shared"
+)
+
+// flatFieldsCache caches the key to field index lookup of flat struct types,
+// keyed by reflect.Type. A nil entry marks a type that is not flat.
+var flatFieldsCache sync.Map
+
+// flatFieldsFor returns the key to field index lookup used by the fast
+// decoding path, and whether the fast path can be used at all.
+//
+// The fast path is only taken for flat structs, where every field is an
+// exported scalar handled by setValue (string, bool, any or a number of any
+// width) without a type default (shared.Defaulter) to seed absent keys with.
+// The decode options must not read or write the OS environment, set a
+// KeyPrefix or collect repeated keys.
+//
+// Parameters:
+//   - t: The type being decoded into
+//   - do: The decode options for this decode
+//
+// Returns:
+//   - map[string]int: .env key to struct field index
+//   - bool: true if the fast path can be used
+func flatFieldsFor(t reflect.Type, do *option.DecodeOption) (map[string]int, bool) {
+	if do.AutomaticEnv || do.PersistToOSEnv || do.KeyPrefix != "" || do.RepeatedKeysAsSlice ||
+		t.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	if cached, ok := flatFieldsCache.Load(t); ok {
+		fields := cached.(map[string]int)
+		return fields, fields != nil
+	}
+
+	fields := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+
+		name := FieldKey(structField)
+		if name == "" {
+			continue
+		}
+		if !structField.IsExported() || !isFlatKind(structField.Type.Kind()) ||
+			shared.HasTypeDefault(structField.Type) || shared.IsGz64(structField) {
+			fields = nil
+			break
+		}
+		fields[strings.ToUpper(name)] = i
+	}
+
+	flatFieldsCache.Store(t, fields)
+	return fields, fields != nil
+}
+
+// isFlatKind reports whether a field of kind k can be set directly from a
+// single .env value without any nesting.
+func isFlatKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.String, reflect.Bool, reflect.Interface,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// decodeFlat decodes .env content straight into the fields of a flat struct,
+// skipping the intermediate key-value map used by the generic path.
+//
+// Lines are checked exactly like in decode. Like there, the last value of a
+// key wins, and fields are set in declaration order once every line has been
+// read, so both paths report the same errors.
+//
+// Parameters:
+//   - buf: Byte slice containing .env file content
+//   - val: Pointer to the flat struct to populate
+//   - fields: .env key to struct field index, from flatFieldsFor
+//   - do: The decode options controlling line parsing
+//
+// Returns:
+//   - error: An error if a line is malformed or a value cannot be converted
+//     to its field type
+func decodeFlat[T any](buf []byte, val *T, fields map[string]int, do *option.DecodeOption) error {
+	v := reflect.ValueOf(val).Elem()
+	values := make([][]byte, v.NumField())
+	keys := make([]string, v.NumField())
+
+	n := 0
+	for line, err := range splitLines(buf, do.CommentPrefixes) {
+		if err != nil {
+			return err
+		}
+		if err := checkStrict(line, do); err != nil {
+			return err
+		}
+		key, value, ok := parseLine(line, do)
+		if !ok {
+			continue
+		}
+		if err := checkValueLen(key, value, do.MaxValueLen); err != nil {
+			return err
+		}
+		n++
+		if err := checkKeyCount(n, do.MaxKeys); err != nil {
+			return err
+		}
+		value, err := resolveSecret(key, value, do)
+		if err != nil {
+			return err
+		}
+
+		if i, ok := fields[string(key)]; ok {
+			values[i] = value
+			keys[i] = string(key)
+		}
+	}
+
+	for i, value := range values {
+		if keys[i] == "" {
+			continue
+		}
+		if err := setValue(v.Field(i), string(value), do); err != nil {
+			return newError(keys[i], "%w", err)
+		}
+	}
+	return nil
+}
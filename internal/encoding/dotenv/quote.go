@@ -0,0 +1,153 @@
+// Package dotenv
+package dotenv
+
+import (
+	"bytes"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// splitLines splits .env content into logical lines.
+//
+// A line is usually one physical line, but a value that opens with a double
+// quote continues across physical lines until its closing quote, so values
+// such as PEM keys can hold newlines:
+//
+//	TLS_KEY="-----BEGIN KEY-----
+//	MIIEvQIBADANBg...
+//	-----END KEY-----"
+//
+// The physical lines of such a value are joined with "\n". Comment lines
+// never open a quoted value, so a quote in a comment such as
+// # example: NAME="my app does not swallow the keys that follow it.
+//
+// Parameters:
+//   - buf: Byte slice containing .env file content
+//   - prefixes: The comment markers to recognize, defaulting to "#" when empty
+//
+// Returns:
+//   - iter.Seq2[[]byte, error]: The logical lines of buf. A quote that is
+//     never closed ends the sequence with an error naming its key and line
+func splitLines(buf []byte, prefixes []string) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		var pending, pendingKey []byte
+		pendingLine := 0
+		n := 0
+		for line := range bytes.SplitSeq(buf, []byte{'\n'}) {
+			n++
+			line = bytes.TrimSuffix(line, []byte{'\r'})
+			if pending != nil {
+				pending = append(append(pending, '\n'), line...)
+				if !closesQuote(line) {
+					continue
+				}
+				line, pending = pending, nil
+			} else if key, ok := opensQuote(line, prefixes); ok {
+				pending = append([]byte(nil), line...)
+				pendingKey, pendingLine = key, n
+				continue
+			}
+			if !yield(line, nil) {
+				return
+			}
+		}
+		if pending != nil {
+			yield(nil, fmt.Errorf("unterminated quoted value of %s starting at line %d", pendingKey, pendingLine))
+		}
+	}
+}
+
+// opensQuote reports whether the value of a physical line starts a double
+// quoted value that is not closed on the same line, and returns its key.
+// Comment lines never open a quoted value.
+func opensQuote(line []byte, prefixes []string) ([]byte, bool) {
+	line = bytes.TrimLeft(line, " \t")
+	if len(stripComment(line, prefixes)) == 0 {
+		return nil, false
+	}
+	key, value, ok := bytes.Cut(stripExport(line), []byte("="))
+	if !ok {
+		return nil, false
+	}
+	value = bytes.TrimLeft(value, " \t")
+	if len(value) == 0 || value[0] != '"' {
+		return nil, false
+	}
+	_, _, closed := cutQuoted(value)
+	return bytes.TrimSpace(key), !closed
+}
+
+// closesQuote reports whether a continuation line of a quoted value holds
+// the closing quote.
+func closesQuote(line []byte) bool {
+	_, _, closed := cutQuoted(append([]byte{'"'}, line...))
+	return closed
+}
+
+// cutQuoted reads a double quoted value from the start of value.
+//
+// Inside the quotes \" stands for a quote and \\ for a backslash; any other
+// backslash is kept as is.
+//
+// Parameters:
+//   - value: The value, starting with the opening quote
+//
+// Returns:
+//   - string: The unescaped content between the quotes
+//   - []byte: Whatever follows the closing quote
+//   - bool: false if value is not a closed quoted value
+func cutQuoted(value []byte) (string, []byte, bool) {
+	if len(value) == 0 || value[0] != '"' {
+		return "", nil, false
+	}
+
+	var build strings.Builder
+	for i := 1; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			if i+1 < len(value) && (value[i+1] == '"' || value[i+1] == '\\') {
+				i++
+			}
+			build.WriteByte(value[i])
+		case '"':
+			return build.String(), value[i+1:], true
+		default:
+			build.WriteByte(value[i])
+		}
+	}
+	return "", nil, false
+}
+
+// formatValue returns an encoded value as it is written to a .env file. Values
+// that would not read back unchanged when written raw are quoted with
+// QuoteValue: values holding whitespace, a "#", an "=", a quote, a newline or
+// a backslash.
+//
+// Example:
+//
+//	formatValue([]byte("8080"))        // Returns: 8080
+//	formatValue([]byte("red # blue")) // Returns: "red # blue"
+func formatValue(value []byte) []byte {
+	if !bytes.ContainsAny(value, " \t#=\"'\\\n\r") {
+		return value
+	}
+	return QuoteValue(string(value))
+}
+
+// QuoteValue wraps a value in double quotes, escaping quotes and backslashes,
+// so that cutQuoted reads it back unchanged.
+//
+// Example:
+//
+//	QuoteValue("line one\nline two") // Returns: "\"line one\nline two\""
+func QuoteValue(s string) []byte {
+	build := []byte{'"'}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			build = append(build, '\\')
+		}
+		build = append(build, s[i])
+	}
+	return append(build, '"')
+}
@@ -5,13 +5,23 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
-	utility "github.com/ahyalfan/gathuk/internal/utils"
+	"github.com/ahyalfan/gathuk/option"
 	"github.com/ahyalfan/gathuk/shared"
 )
 
+var (
+	// durationType is the reflect.Type of time.Duration
+	durationType = reflect.TypeOf(time.Duration(0))
+	// timeType is the reflect.Type of time.Time
+	timeType = reflect.TypeOf(time.Time{})
+)
+
 // scanWithNestedPrefix initiates the recursive scanning process to populate
 // a struct from the parsed key-value pairs.
 //
@@ -19,20 +29,29 @@ import (
 // scanning process.
 //
 // Parameters:
-//   - v: Pointer to the configuration struct to populate
+//   - v: Pointer to the configuration struct to populate, usually a *T, or
+//     a pointer of any type when called through DecodeInto
 //
 // Returns:
 //   - error: An error if scanning fails
 //
 // Panics if v is not a pointer.
-func (c *Codec[T]) scanWithNestedPrefix(v *T) error {
+func (c *Codec[T]) scanWithNestedPrefix(v any) error {
 	if reflect.TypeOf(v).Kind() != reflect.Ptr {
 		panic("value is not pointer")
 	}
 
 	vt := reflect.ValueOf(v).Elem()
-	parent := reflect.TypeOf(v)
-	err := c.scanNestedWithNestedPrefix(parent, vt, "")
+	if vt.Type() == shared.OrderedMapType {
+		m, err := c.toOrderedMap()
+		if err != nil {
+			return err
+		}
+		vt.Set(reflect.ValueOf(m))
+		return nil
+	}
+
+	err := c.scanNestedWithNestedPrefix(0, vt, c.keyPrefix(vt))
 
 	return err
 }
@@ -42,18 +61,32 @@ func (c *Codec[T]) scanWithNestedPrefix(v *T) error {
 //
 // This method processes each field of the struct:
 //   - For nested structs: Recursively processes with the appropriate prefix
+//   - For pointers to nested structs: Allocates the struct only when a key
+//     with its prefix exists, leaving the pointer nil otherwise
+//   - For maps of nested structs: Groups the keys under the field prefix by
+//     their next segment, see toStructMap
 //   - For basic types: Maps configuration keys to field values
+//   - For embedded structs without a tag: Promotes their fields to this level
+//   - For catch-all maps: Collects the file keys under the struct's prefix
+//     that were not assigned to any declared field
 //   - Respects `config` and `nested` struct tags
 //   - Handles environment variable fallback based on DecodeOption
 //
+// Recursive struct types, such as a struct holding a pointer to itself, are
+// followed at most maxNestingDepth levels deep; deeper nesting is an error.
+//
 // Parameters:
-//   - parent: The parent type (used to prevent infinite recursion)
+//   - depth: The nesting level of v, 0 for the root
 //   - v: The reflect.Value of the struct to populate
 //   - nestedPrefix: The prefix to prepend to field names (e.g., "DB_" for nested database config)
 func (c *Codec[T]) scanNestedWithNestedPrefix(
-	parent reflect.Type, v reflect.Value, nestedPrefix string,
+	depth int, v reflect.Value, nestedPrefix string,
 ) error {
-	if !v.CanSet() {
+	if depth > maxNestingDepth {
+		return depthError(nestedPrefix)
+	}
+	// fields of an unexported embedded struct can still be set one by one
+	if !v.CanSet() && v.Kind() != reflect.Struct {
 		return newError(nestedPrefix, "value not settable")
 	}
 
@@ -65,77 +98,137 @@ func (c *Codec[T]) scanNestedWithNestedPrefix(
 		}
 		v.Set(reflect.ValueOf(native))
 	case reflect.Struct:
+		catchAll := -1
 		for i := 0; i < v.NumField(); i++ {
 			field := v.Field(i)
 			structField := v.Type().Field(i)
 
-			if structField.Type.Kind() == reflect.Struct && structField.Type != parent {
-				nestedName := structField.Tag.Get(string(shared.GetTagNestedName()))
-				if nestedName == "-" {
-					continue
-				}
-				if nestedName == "" {
-					nestedName = structField.Tag.Get(string(shared.GetTagName()))
-					if nestedName == "-" {
-						continue
-					}
+			if shared.IsCatchAll(structField) {
+				catchAll = i
+				continue
+			}
+
+			if isPromoted(structField) {
+				err := c.scanNestedWithNestedPrefix(depth+1, field, nestedPrefix)
+				if err != nil {
+					return err
 				}
+				continue
+			}
 
+			if isNestedStruct(structField.Type) {
+				nestedName := FieldKey(structField)
 				if nestedName == "" {
-					nestedName = structField.Tag.Get("env")
-					if nestedName == "-" {
-						continue
-					}
+					continue
+				}
+				if nestedPrefix != "" {
+					nestedName = nestedPrefix + c.prefixSeparator(nestedPrefix) + nestedName
 				}
+				err := c.scanNestedWithNestedPrefix(depth+1, field, nestedName)
+				if err != nil {
+					return err
+				}
+				continue
+			}
 
-				if nestedName == "" {
-					nestedName = utility.PascalToUpperSnakeCase(structField.Name)
+			if isStructMap(structField.Type) {
+				nestedName := FieldKey(structField)
+				if nestedName == "" || !field.CanSet() {
+					continue
 				}
 				if nestedPrefix != "" {
-					nestedName = nestedPrefix + "_" + nestedName
+					nestedName = nestedPrefix + c.prefixSeparator(nestedPrefix) + nestedName
 				}
-				err := c.scanNestedWithNestedPrefix(parent, field, nestedName)
+				err := c.toStructMap(depth+1, field, nestedName)
 				if err != nil {
 					return err
 				}
 				continue
 			}
 
-			var name string
-			name = structField.Tag.Get(string(shared.GetTagName()))
-			if name == "-" {
+			if structField.Type.Kind() == reflect.Map {
+				nestedName := FieldKey(structField)
+				if nestedName == "" || !field.CanSet() {
+					continue
+				}
+				if nestedPrefix != "" {
+					nestedName = nestedPrefix + c.prefixSeparator(nestedPrefix) + nestedName
+				}
+				err := c.scanMapField(field, strings.ToUpper(nestedName)+c.separator())
+				if err != nil {
+					return err
+				}
 				continue
 			}
-			if name == "" {
-				name = structField.Tag.Get("env")
-				if name == "-" {
+
+			if isStructPtr(structField.Type) {
+				nestedName := FieldKey(structField)
+				if nestedName == "" || !field.CanSet() {
 					continue
 				}
+				if nestedPrefix != "" {
+					nestedName = nestedPrefix + c.prefixSeparator(nestedPrefix) + nestedName
+				}
+				if !c.hasKeyWithPrefix(nestedName + c.separator()) {
+					continue
+				}
+				if field.IsNil() {
+					field.Set(reflect.New(structField.Type.Elem()))
+				}
+				err := c.scanNestedWithNestedPrefix(depth+1, field.Elem(), nestedName)
+				if err != nil {
+					return err
+				}
+				continue
 			}
 
+			name := FieldKey(structField)
 			if name == "" {
-				name = utility.PascalToUpperSnakeCase(structField.Name)
+				continue
 			}
 
 			if nestedPrefix != "" {
-				sub := nestedPrefix + "_"
+				sub := nestedPrefix + c.prefixSeparator(nestedPrefix)
 				name = sub + name
 			}
 			name = strings.ToUpper(name)
 
 			val, ok := c.temp[name]
-
-			if !ok || !field.CanSet() {
+			if !ok {
+				if err := shared.ApplyTypeDefault(field); err != nil {
+					return newError(name, "%w", err)
+				}
+				continue
+			}
+			if !field.CanSet() {
 				continue
 			}
+			c.markUsed(name)
 
-			err := setValue(field, string(val))
+			do, err := fieldDecodeOption(structField, c.decodeOption())
+			if err != nil {
+				return newError(name, "%w", err)
+			}
+			if values, ok := c.repeated[name]; ok && isListField(field) {
+				err = setRepeated(field, values, do)
+			} else if shared.IsGz64(structField) {
+				err = setGz64(field, string(val), do)
+			} else {
+				err = setValue(field, string(val), do)
+			}
+			if err != nil {
+				return newError(name, "%w", err)
+			}
+		}
+
+		if catchAll >= 0 {
+			err := c.scanCatchAll(v.Field(catchAll), nestedPrefix)
 			if err != nil {
 				return err
 			}
 		}
 	case reflect.Map:
-		err := c.toMap(v, nestedPrefix)
+		err := c.toMap(depth, v, nestedPrefix)
 		if err != nil {
 			return err
 		}
@@ -146,6 +239,130 @@ func (c *Codec[T]) scanNestedWithNestedPrefix(
 	return nil
 }
 
+// scanCatchAll fills a catch-all map with the keys read from the file that
+// were not assigned to a declared field.
+//
+// Only keys under nestedPrefix are collected, and the prefix is stripped from
+// the map keys. Keys that only came from the OS environment are never
+// collected. Collected keys are marked as used, so a catch-all field of an
+// outer struct does not receive them again.
+//
+// Parameters:
+//   - field: The catch-all map value to populate
+//   - nestedPrefix: The prefix of the struct holding the catch-all field
+//
+// Returns:
+//   - error: An error if a value cannot be converted to the map value type
+func (c *Codec[T]) scanCatchAll(field reflect.Value, nestedPrefix string) error {
+	mapType := field.Type()
+	if mapType.Key().Kind() != reflect.String {
+		return newError(nestedPrefix, "catch-all map key must be string, got %s", mapType.Key())
+	}
+
+	prefix := ""
+	if nestedPrefix != "" {
+		prefix = strings.ToUpper(nestedPrefix) + c.prefixSeparator(nestedPrefix)
+	}
+
+	for k := range c.fileKeys {
+		if _, ok := c.used[k]; ok || !strings.HasPrefix(k, prefix) {
+			continue
+		}
+
+		elemValue := reflect.New(mapType.Elem()).Elem()
+		err := setValue(elemValue, string(c.temp[k]), c.decodeOption())
+		if err != nil {
+			return newError(k, "%w", err)
+		}
+
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(mapType))
+		}
+		field.SetMapIndex(reflect.ValueOf(strings.TrimPrefix(k, prefix)).Convert(mapType.Key()), elemValue)
+		c.markUsed(k)
+	}
+	return nil
+}
+
+// scanMapField fills a map field of a struct, including named map types such
+// as `type Headers map[string]string`, from the keys under prefix. The prefix
+// is stripped and the rest converted to the map key type, so HEADERS_ACCEPT
+// becomes the entry "ACCEPT" of a field keyed HEADERS. Like in the JSON codec,
+// a source holding any such key replaces the whole map; the existing map is
+// left untouched, as it may be shared with the configuration being merged
+// into.
+//
+// Parameters:
+//   - field: The map value to populate
+//   - prefix: The key of the field followed by the separator
+//
+// Returns:
+//   - error: An error if a key or value cannot be converted
+func (c *Codec[T]) scanMapField(field reflect.Value, prefix string) error {
+	mapType := field.Type()
+	if !shared.IsMapKeyType(mapType.Key()) {
+		return newError(prefix, "map key must be a string, number or bool, got %s", mapType.Key())
+	}
+
+	var newMap reflect.Value
+	for k, v := range c.temp {
+		if !strings.HasPrefix(k, prefix) || len(k) == len(prefix) {
+			continue
+		}
+
+		keyValue := reflect.New(mapType.Key()).Elem()
+		if err := setValue(keyValue, strings.TrimPrefix(k, prefix), c.decodeOption()); err != nil {
+			return newError(k, "map key: %w", err)
+		}
+		elemValue := reflect.New(mapType.Elem()).Elem()
+		if err := setValue(elemValue, string(v), c.decodeOption()); err != nil {
+			return newError(k, "%w", err)
+		}
+
+		if !newMap.IsValid() {
+			newMap = reflect.MakeMap(mapType)
+		}
+		newMap.SetMapIndex(keyValue, elemValue)
+		c.markUsed(k)
+	}
+	if newMap.IsValid() {
+		field.Set(newMap)
+	}
+	return nil
+}
+
+// markUsed records that a key was assigned during scanning.
+func (c *Codec[T]) markUsed(key string) {
+	if c.used != nil {
+		c.used[key] = struct{}{}
+	}
+}
+
+// hasKeyWithPrefix reports whether any parsed key starts with prefix.
+//
+// It is used to decide whether a nil pointer-to-struct field needs to be
+// allocated: pointers without any matching key are left nil.
+func (c *Codec[T]) hasKeyWithPrefix(prefix string) bool {
+	prefix = strings.ToUpper(prefix)
+	for k := range c.temp {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isStructPtr reports whether t is a pointer to a nested struct type.
+func isStructPtr(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && isNestedStruct(t.Elem())
+}
+
+// isNestedStruct reports whether t is a struct whose fields map to their own
+// keys. Scalar structs such as time.Time are decoded from a single value.
+func isNestedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !shared.IsScalarStruct(t)
+}
+
 // toMap converts the parsed key-value pairs into a map[string]V where V is the map value type.
 //
 // This method is used when the target type is a map instead of a struct.
@@ -157,20 +374,30 @@ func (c *Codec[T]) scanNestedWithNestedPrefix(
 // The map key in the result includes the prefix, so for prefix "DB_" and key "DB_HOST",
 // the map will contain "DB_HOST" as the key (not just "HOST").
 //
+// Keys are converted to the key type of the map like values, so a
+// map[int]string reads "80=http"; a key that does not convert is an error.
+//
+// Maps of nested structs, such as map[string]Database, are decoded by
+// toStructMap instead.
+//
 // Type conversion is handled automatically:
 //   - If the target map value type is string, values are used as-is
 //   - If the target type is int/float/bool, string values are parsed accordingly
 //   - If parsing fails, an error is returned
 //
 // Parameters:
+//   - depth: The nesting level of v, 0 for the root
 //   - v: The reflect.Value of the map to populate
 //   - prefix: Optional prefix to filter keys (e.g., "DB_" for database configs)
 //
 // Returns:
 //   - error: An error if type conversion or map creation fails
-func (c *Codec[T]) toMap(v reflect.Value, prefix string) error {
-	if v.Type().Key().Kind() != reflect.String {
-		return newError(prefix, "map key must be string, got %s", v.Type().Key())
+func (c *Codec[T]) toMap(depth int, v reflect.Value, prefix string) error {
+	if isStructMap(v.Type()) {
+		return c.toStructMap(depth, v, prefix)
+	}
+	if !shared.IsMapKeyType(v.Type().Key()) {
+		return newError(prefix, "map key must be a string, number or bool, got %s", v.Type().Key())
 	}
 
 	mapType := v.Type()
@@ -187,19 +414,111 @@ func (c *Codec[T]) toMap(v reflect.Value, prefix string) error {
 			nested = k
 		}
 
+		keyValue := reflect.New(mapType.Key()).Elem()
+		if err := setValue(keyValue, nested, c.decodeOption()); err != nil {
+			return newError(prefix, "map key %q: %w", nested, err)
+		}
+
 		elemValue := reflect.New(mapType.Elem()).Elem()
 
-		err := setValue(elemValue, string(v))
+		err := setValue(elemValue, string(v), c.decodeOption())
 		if err != nil {
-			return err
+			return newError(k, "%w", err)
 		}
 
-		newMap.SetMapIndex(reflect.ValueOf(nested), elemValue)
+		newMap.SetMapIndex(keyValue, elemValue)
+		c.markUsed(k)
 	}
 	v.Set(newMap)
 	return nil
 }
 
+// isStructMap reports whether t is a map whose values are nested structs or
+// pointers to nested structs, such as map[string]Database.
+func isStructMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && (isNestedStruct(t.Elem()) || isStructPtr(t.Elem()))
+}
+
+// toStructMap decodes grouped keys into a map of nested structs.
+//
+// Every key under prefix is split at the first nested separator ("_" by
+// default, see DecodeOption.NestedSeparator) after the prefix: the
+// first segment names the map entry, and the rest is decoded into the entry
+// like the fields of a nested struct. So for a field tagged `config:"dbs"` of
+// type map[string]Database, DBS_PRIMARY_HOST and DBS_PRIMARY_USER fill
+// "PRIMARY", and DBS_REPLICA_HOST fills "REPLICA". Entry names therefore
+// cannot contain the separator, and are read in upper case like all .env
+// keys.
+//
+// Existing entries are decoded into in place, so keys absent from the source
+// keep their values. Groups that set no field do not add an entry, and the
+// map is left untouched when no key has the prefix.
+//
+// Parameters:
+//   - depth: The nesting level of v
+//   - v: The reflect.Value of the map to populate
+//   - prefix: The prefix of the grouped keys, empty for a root map
+//
+// Returns:
+//   - error: An error if an entry name does not convert to the map key type,
+//     or an entry cannot be decoded
+func (c *Codec[T]) toStructMap(depth int, v reflect.Value, prefix string) error {
+	mapType := v.Type()
+	if !shared.IsMapKeyType(mapType.Key()) {
+		return newError(prefix, "map key must be a string, number or bool, got %s", mapType.Key())
+	}
+
+	keyPrefix := ""
+	if prefix != "" {
+		keyPrefix = strings.ToUpper(prefix) + c.separator()
+	}
+
+	groups := make(map[string]struct{})
+	for k := range c.temp {
+		rest, ok := strings.CutPrefix(k, keyPrefix)
+		if !ok {
+			continue
+		}
+		if group, _, ok := strings.Cut(rest, c.separator()); ok && group != "" {
+			groups[group] = struct{}{}
+		}
+	}
+
+	for group := range groups {
+		keyValue := reflect.New(mapType.Key()).Elem()
+		if err := setValue(keyValue, group, c.decodeOption()); err != nil {
+			return newError(prefix, "map key %q: %w", group, err)
+		}
+
+		elem := reflect.New(mapType.Elem()).Elem()
+		existing := v.MapIndex(keyValue)
+		if existing.IsValid() {
+			elem.Set(existing)
+		}
+		target := elem
+		if elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				elem.Set(reflect.New(mapType.Elem().Elem()))
+			}
+			target = elem.Elem()
+		}
+
+		err := c.scanNestedWithNestedPrefix(depth+1, target, keyPrefix+group)
+		if err != nil {
+			return err
+		}
+		if !existing.IsValid() && target.IsZero() {
+			continue
+		}
+
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(mapType))
+		}
+		v.SetMapIndex(keyValue, elem)
+	}
+	return nil
+}
+
 // toNative converts the parsed key-value pairs into native Go types for interface{}/any.
 //
 // This method is used when the target type is interface{} or any. It creates a
@@ -227,11 +546,44 @@ func (c *Codec[T]) toNative(prefix string) (any, error) {
 			}
 		}
 		var converted any
-		err := setValue(reflect.ValueOf(&converted).Elem(), string(v))
+		err := setValue(reflect.ValueOf(&converted).Elem(), string(v), c.decodeOption())
 		if err != nil {
-			return nil, newError(prefix, "%v", err)
+			return nil, newError(k, "%w", err)
 		}
 		m[k] = converted
+		c.markUsed(k)
+	}
+	return m, nil
+}
+
+// toOrderedMap converts the parsed key-value pairs into an OrderedMap.
+//
+// Keys read from the file come first, in the order they appear in the file.
+// Keys merged from the OS environment (AutomaticEnv) follow in sorted order.
+// Values are converted to native types like toNative.
+//
+// Returns:
+//   - shared.OrderedMap: The ordered key-value pairs
+//   - error: An error if conversion fails
+func (c *Codec[T]) toOrderedMap() (shared.OrderedMap, error) {
+	keys := append([]string{}, c.order...)
+	var envKeys []string
+	for k := range c.temp {
+		if _, ok := c.fileKeys[k]; !ok {
+			envKeys = append(envKeys, k)
+		}
+	}
+	sort.Strings(envKeys)
+	keys = append(keys, envKeys...)
+
+	m := make(shared.OrderedMap, 0, len(keys))
+	for _, k := range keys {
+		var converted any
+		err := setValue(reflect.ValueOf(&converted).Elem(), string(c.temp[k]), c.decodeOption())
+		if err != nil {
+			return nil, newError(k, "%w", err)
+		}
+		m = append(m, shared.KeyValue{Key: k, Value: converted})
 	}
 	return m, nil
 }
@@ -243,17 +595,34 @@ func (c *Codec[T]) toNative(prefix string) (any, error) {
 //
 // Supported types:
 //   - string: Direct assignment
-//   - int, int64: Parsed as base-10 integer
-//   - float64: Parsed as floating-point number
-//   - bool: Parsed as boolean (true/false)
+//   - int, int8, int16, int32, int64: Parsed as an integer literal with Go
+//     syntax: an optional sign, a 0x, 0o (or leading 0) or 0b prefix and
+//     underscores between digits, e.g. "+42", "0x1F", "0o17", "1_000".
+//     Fractions and exponents such as "1e3" are rejected, as are values
+//     that overflow the field type
+//   - uint, uint8, uint16, uint32, uint64: Parsed like signed integers,
+//     without a minus sign
+//   - float32, float64: Parsed as a decimal or hexadecimal floating-point
+//     number with an optional sign, exponent and underscores between digits,
+//     e.g. "+1.5", "1e3", "1_000.5"
+//   - Named types of these kinds, e.g. `type Port uint16`
+//   - bool: Parsed as boolean (true/false, 1/0, ...); with
+//     DecodeOption.ExtendedBool also yes/no, y/n and on/off
+//   - time.Duration: Parsed with time.ParseDuration (e.g. "1m30s")
+//   - time.Time: Parsed as RFC 3339 (e.g. "2024-01-02T15:04:05Z"), or without
+//     a zone (e.g. "2024-01-02 15:04:05") in DecodeOption.DefaultLocation
+//   - slices: Comma-separated list, each element converted by setValue
+//     (e.g. "1s,2s,4s" for []time.Duration)
 //   - any: Parsed any value
 //
 // Parameters:
 //   - field: The reflect.Value of the field to set
 //   - val: The string value to convert and assign
+//   - do: The decode options controlling conversion
 //
-// return error if type conversion fails.
-func setValue(field reflect.Value, val string) error {
+// return error if type conversion fails. setValue does not know the key of
+// val, so callers wrap the error with newError to name it.
+func setValue(field reflect.Value, val string, do *option.DecodeOption) error {
 	if field.Kind() == reflect.Ptr {
 		if field.IsNil() {
 			field.Set(reflect.New(field.Type().Elem()))
@@ -261,42 +630,141 @@ func setValue(field reflect.Value, val string) error {
 		field = field.Elem()
 	}
 
+	switch field.Type() {
+	case durationType:
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return conversionError("convert string to duration error: %w", err)
+		}
+		field.SetInt(int64(d))
+		return nil
+	case timeType:
+		t, err := shared.ParseTime(val, do.DefaultLocation)
+		if err != nil {
+			return conversionError("convert string to time error: %w", err)
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	}
+
 	// Basic kinds
 	switch field.Kind() {
 	case reflect.String:
 		field.SetString(val)
-	case reflect.Int, reflect.Int64:
-		i64, err := strconv.ParseInt(val, 0, 64)
+	case reflect.Slice:
+		return setSlice(field, val, do)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i64, err := strconv.ParseInt(val, 0, field.Type().Bits())
 		if err != nil {
-			return newError("", "convert string to int error: %+v", err)
+			return conversionError("convert string to %s error: %w", field.Type(), err)
 		}
 		field.SetInt(i64)
-	case reflect.Float64:
-		f64, err := strconv.ParseFloat(val, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u64, err := strconv.ParseUint(val, 0, field.Type().Bits())
+		if err != nil {
+			return conversionError("convert string to %s error: %w", field.Type(), err)
+		}
+		field.SetUint(u64)
+	case reflect.Float32, reflect.Float64:
+		f64, err := strconv.ParseFloat(val, field.Type().Bits())
 		if err != nil {
-			return newError("", "convert string to float error: %+v", err)
+			return conversionError("convert string to %s error: %w", field.Type(), err)
 		}
 		field.SetFloat(f64)
 	case reflect.Bool:
-		bVal, err := strconv.ParseBool(val)
+		bVal, err := parseBool(val, do.ExtendedBool)
 		if err != nil {
-			return newError("", "convert string to bool error: %+v", err)
+			return conversionError("convert string to bool error: %w", err)
 		}
 		field.SetBool(bVal)
 	case reflect.Interface:
-		var converted any
-		if b, err := strconv.ParseBool(val); err == nil {
-			converted = b
-		} else if i, err := strconv.ParseInt(val, 10, 64); err == nil {
-			converted = i
-		} else if f, err := strconv.ParseFloat(val, 64); err == nil {
-			converted = f
-		} else {
-			converted = val
+		// same dynamic types as the JSON codec, see shared.InferScalar
+		field.Set(reflect.ValueOf(shared.InferScalar(val)))
+	}
+	return nil
+}
+
+// isListField reports whether field is a slice filled from a list of values,
+// as opposed to []byte, which holds a single value.
+func isListField(field reflect.Value) bool {
+	t := field.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Slice && !shared.IsBytes(t)
+}
+
+// setRepeated sets a slice field from every value of a key listed several
+// times, such as TAG=a and TAG=b. Each value is read like a single value
+// with setSlice, and the elements are concatenated in order.
+//
+// Parameters:
+//   - field: The slice value to set
+//   - values: The values of the key, in file order
+//   - do: The decode options passed on to setValue
+//
+// return error if an element cannot be converted.
+func setRepeated(field reflect.Value, values [][]byte, do *option.DecodeOption) error {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
 		}
+		field = field.Elem()
+	}
 
-		field.Set(reflect.ValueOf(converted))
+	all := reflect.MakeSlice(field.Type(), 0, len(values))
+	for i, val := range values {
+		part := reflect.New(field.Type()).Elem()
+		if err := setSlice(part, string(val), do); err != nil {
+			return fmt.Errorf("occurrence %d: %w", i+1, err)
+		}
+		all = reflect.AppendSlice(all, part)
 	}
+	field.Set(all)
+	return nil
+}
+
+// setSlice sets a slice field from a comma-separated list. Elements are
+// trimmed of surrounding whitespace and converted with setValue, so every
+// element type supported by setValue (including time.Duration) works.
+// An empty value yields an empty slice.
+//
+// Parameters:
+//   - field: The slice value to set
+//   - val: The comma-separated list
+//   - do: The decode options passed on to setValue
+//
+// return error if an element cannot be converted.
+func setSlice(field reflect.Value, val string, do *option.DecodeOption) error {
+	if strings.TrimSpace(val) == "" {
+		field.Set(reflect.MakeSlice(field.Type(), 0, 0))
+		return nil
+	}
+
+	parts := strings.Split(val, ",")
+	slice := reflect.MakeSlice(field.Type(), len(parts), len(parts))
+	for i, part := range parts {
+		err := setValue(slice.Index(i), strings.TrimSpace(part), do)
+		if err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	field.Set(slice)
+	return nil
+}
+
+// setGz64 sets a []byte field marked with the `gz64` option. A "gz64:" value
+// is base64 decoded and gunzipped, up to DecodeOption.MaxValueLen bytes (or
+// shared.MaxGz64Len when unset); other values are set by setValue.
+func setGz64(field reflect.Value, val string, do *option.DecodeOption) error {
+	data, ok, err := shared.DecodeGz64(val, do.MaxValueLen)
+	if err != nil {
+		return conversionError("%w", err)
+	}
+	if !ok {
+		return setValue(field, val, do)
+	}
+	field.SetBytes(data)
 	return nil
 }
 
@@ -344,3 +812,68 @@ func newError(path, format string, args ...any) error {
 	}
 	return fmt.Errorf("ast unmarshal error: "+format, args...)
 }
+
+// locationCache caches the locations loaded for `tz` tags by zone name.
+var locationCache sync.Map
+
+// fieldDecodeOption returns the decode options for a single struct field. A
+// `tz` tag (e.g. `tz:"America/New_York"`) replaces DefaultLocation, so
+// timestamps without a zone are read in the zone of that field.
+//
+// Parameters:
+//   - sf: The struct field being decoded
+//   - do: The decode options of the codec
+//
+// Returns the options to decode the field with, or an error if the `tz` tag
+// does not name a known time zone.
+func fieldDecodeOption(sf reflect.StructField, do *option.DecodeOption) (*option.DecodeOption, error) {
+	tz, ok := sf.Tag.Lookup("tz")
+	if !ok {
+		return do, nil
+	}
+
+	loc, ok := locationCache.Load(tz)
+	if !ok {
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tz tag %q: %w", tz, err)
+		}
+		loc, _ = locationCache.LoadOrStore(tz, l)
+	}
+
+	fdo := *do
+	fdo.DefaultLocation = loc.(*time.Location)
+	return &fdo, nil
+}
+
+// parseBool parses a boolean like strconv.ParseBool. When extended is set,
+// the common config spellings yes/no, y/n and on/off are accepted as well,
+// in any case.
+func parseBool(val string, extended bool) (bool, error) {
+	if extended {
+		switch strings.ToLower(val) {
+		case "yes", "y", "on":
+			return true, nil
+		case "no", "n", "off":
+			return false, nil
+		}
+	}
+	return strconv.ParseBool(val)
+}
+
+// maxNestingDepth is the deepest level of nested structs the codec follows.
+// It stops recursive struct types, e.g. A holding a *B holding an *A, from
+// recursing until the stack overflows.
+const maxNestingDepth = 32
+
+// depthError reports nesting deeper than maxNestingDepth at key prefix.
+func depthError(prefix string) error {
+	return newError(prefix, "struct nesting exceeds the maximum depth of %d, the struct type or value may be cyclic", maxNestingDepth)
+}
+
+// conversionError reports a value that cannot be converted to its field
+// type; the error matches shared.ErrTypeConversion. setValue does not know
+// the key it converts, so callers name it with newError.
+func conversionError(format string, args ...any) error {
+	return shared.WrapError(fmt.Errorf(format, args...), shared.ErrTypeConversion)
+}
@@ -27,6 +27,9 @@ type ASTNode interface {
 // Example JSON: {"name": "John", "age": 30}
 type ObjectNode struct {
 	Value map[string]ASTNode
+	// Keys holds the keys of Value in document order. It is filled by the
+	// parser; when it does not cover every key, the order is unspecified.
+	Keys []string
 }
 
 // ArrayNode represents a JSON array in the AST.
@@ -56,6 +59,24 @@ type NumberNode struct {
 	Value float64
 }
 
+// IntegerNode represents a JSON number without a fraction or exponent in the AST.
+//
+// Integers are kept as int64 instead of going through float64, so values
+// beyond 2^53 (such as large IDs) keep their exact value.
+//
+// Example JSON: 8080, -42, 9223372036854775807
+type IntegerNode struct {
+	Value int64
+}
+
+// UnsignedNode represents a JSON integer above the int64 range that still
+// fits in a uint64, so values up to 2^64-1 keep their exact value too.
+//
+// Example JSON: 18446744073709551615
+type UnsignedNode struct {
+	Value uint64
+}
+
 // BooleanNode represents a JSON boolean value in the AST.
 //
 // Example JSON: true, false
@@ -88,6 +109,16 @@ func (n NumberNode) Type() string {
 	return "Number"
 }
 
+// Type returns "Integer" for IntegerNode.
+func (i IntegerNode) Type() string {
+	return "Integer"
+}
+
+// Type returns "Unsigned" for UnsignedNode.
+func (u UnsignedNode) Type() string {
+	return "Unsigned"
+}
+
 // Type returns "Boolean" for BooleanNode.
 func (b BooleanNode) Type() string {
 	return "Boolean"
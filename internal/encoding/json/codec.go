@@ -2,6 +2,9 @@
 package json
 
 import (
+	"fmt"
+	"reflect"
+
 	"github.com/ahyalfan/gathuk/option"
 )
 
@@ -78,7 +81,8 @@ func (c *Codec[T]) CheckEncodeOption() bool {
 //
 // Returns:
 //   - []byte: The encoded JSON data
-//   - error: An error if encoding fails
+//   - error: An error if encoding fails, or EncodeOption.Header is set, as
+//     JSON has no comment syntax to carry it
 //
 // Example:
 //
@@ -91,6 +95,10 @@ func (c *Codec[T]) CheckEncodeOption() bool {
 //	data, err := codec.Encode(Config{Port: 8080, Host: "localhost"})
 //	// data contains: {"port": 8080, "host": "localhost"}
 func (c *Codec[T]) Encode(val T) ([]byte, error) {
+	if c.eo != nil && c.eo.Header != "" {
+		return nil, fmt.Errorf("encode header: JSON has no comment syntax to write %q", c.eo.Header)
+	}
+
 	astN, err := c.StructToAST(&val)
 	if err != nil {
 		return nil, err
@@ -100,6 +108,72 @@ func (c *Codec[T]) Encode(val T) ([]byte, error) {
 	return r, err
 }
 
+// ToMap converts a configuration struct to nested native values instead of
+// JSON bytes, keyed like Encode would key them.
+//
+// The struct is converted to an AST as in Encode, and the AST is then walked
+// like decoding into an interface{} does: objects become map[string]any,
+// arrays []any, and scalars string, int64, uint64 (beyond the int64 range),
+// float64 or bool.
+//
+// Parameters:
+//   - val: The configuration struct to convert
+//
+// Returns:
+//   - map[string]any: The converted configuration
+//   - error: An error if conversion fails or val is not encoded as an object
+//
+// Example:
+//
+//	m, err := codec.ToMap(Config{Port: 8080, Host: "localhost"})
+//	// m: map[string]any{"port": int64(8080), "host": "localhost"}
+func (c *Codec[T]) ToMap(val T) (map[string]any, error) {
+	astN, err := c.StructToAST(&val)
+	if err != nil {
+		return nil, err
+	}
+	native, err := c.toNative(astN, "")
+	if err != nil {
+		return nil, err
+	}
+	m, ok := native.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("cannot convert %T to a map", val)
+	}
+	return m, nil
+}
+
+// Bind decodes a tree of native values, such as a map[string]any obtained
+// from ToMap, into dst, which may be a pointer to any type, not just T.
+//
+// src is converted to an AST as Encode would convert it, and the AST is then
+// mapped onto dst exactly like a decoded JSON document, with the decode
+// options of this codec. Keys absent from src leave the values of dst
+// untouched.
+//
+// Parameters:
+//   - src: The values to bind, e.g. map[string]any{"host": "localhost"}
+//   - dst: Non-nil pointer to the value to populate
+//
+// Returns:
+//   - error: An error if dst is not a non-nil pointer or a value does not fit
+//
+// Example:
+//
+//	var db Database
+//	err := codec.Bind(map[string]any{"host": "localhost", "port": 5432}, &db)
+func (c *Codec[T]) Bind(src, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("bind destination must be a non-nil pointer, got %T", dst)
+	}
+	node, err := c.valueToNode(reflect.ValueOf(&src).Elem(), "")
+	if err != nil {
+		return err
+	}
+	return c.nodeToValue(node, rv.Elem(), "")
+}
+
 // ApplyDecodeOption sets the decode options for this codec.
 //
 // These options control how the codec behaves when decoding JSON to structs,
@@ -125,6 +199,86 @@ func (c *Codec[T]) CheckDecodeOption() bool {
 	return c.do != nil
 }
 
+// Clone returns a new codec carrying the same decode and encode options as c.
+//
+// Options applied to the clone never reach c, so a registered codec can be
+// used with options chosen for a single call.
+//
+// Returns:
+//   - option.Codec[T]: The new codec
+func (c *Codec[T]) Clone() option.Codec[T] {
+	return &Codec[T]{do: c.do, eo: c.eo}
+}
+
+// decodeOption returns the decode options applied to this codec, falling back
+// to the zero DecodeOption when none have been set.
+func (c *Codec[T]) decodeOption() *option.DecodeOption {
+	if c.do == nil {
+		return &option.DecodeOption{}
+	}
+	return c.do
+}
+
+// checkLimits enforces the input limits of DecodeOption on the token stream,
+// so hostile input is rejected before it is parsed into an AST:
+//   - MaxValueLen: every string and number token
+//   - MaxKeys: the total number of object keys, counted by their colons
+//
+// Parameters:
+//   - tokens: The tokens produced by Tokenize
+//   - do: The decode options holding the limits; 0 means unlimited
+//
+// Returns:
+//   - error: An error if any limit is exceeded
+func checkLimits(tokens []Token, do *option.DecodeOption) error {
+	if do.MaxValueLen <= 0 && do.MaxKeys <= 0 {
+		return nil
+	}
+
+	keys := 0
+	for _, token := range tokens {
+		switch token.Type {
+		case String, Number, Integer:
+			if do.MaxValueLen > 0 && len(token.Value) > do.MaxValueLen {
+				return fmt.Errorf("value exceeds maximum length of %d bytes (got %d)", do.MaxValueLen, len(token.Value))
+			}
+		case Colon:
+			keys++
+			if do.MaxKeys > 0 && keys > do.MaxKeys {
+				return fmt.Errorf("input exceeds maximum of %d keys", do.MaxKeys)
+			}
+		}
+	}
+	return nil
+}
+
+// checkControlChars rejects string literals holding unescaped control
+// characters (below 0x20), as required by DecodeOption.StrictJSON. Strings
+// are delimited the same way Tokenize reads them.
+//
+// Parameters:
+//   - input: The JSON bytes, already accepted by Tokenize
+//
+// Returns:
+//   - error: An error naming the line and column of the first control
+//     character, counted like Tokenize counts them
+func checkControlChars(input []byte) error {
+	inString := false
+	line, lineStart := 1, 0
+	for i, char := range input {
+		switch {
+		case char == '"':
+			inString = !inString
+		case inString && char < 0x20:
+			return fmt.Errorf("invalid control character %q in string at line %d column %d", char, line, i-lineStart+1)
+		case char == '\n':
+			line++
+			lineStart = i + 1
+		}
+	}
+	return nil
+}
+
 // Decode parses JSON bytes and populates a configuration struct.
 //
 // The decoding process follows these steps:
@@ -158,17 +312,60 @@ func (c *Codec[T]) Decode(val []byte, dst *T) error {
 	if val == nil {
 		return nil
 	}
-	tokens, err := Tokenize(val)
+	ast, err := c.parse(val)
 	if err != nil {
 		return err
 	}
-	ast, err := Parser(tokens)
+	err = c.ASTToStruct(ast, dst)
 	if err != nil {
 		return err
 	}
-	err = c.ASTToStruct(ast, dst)
+	return nil
+}
+
+// DecodeInto is like Decode, but populates dst, which may point to any type,
+// not just T, so a codec for a whole configuration can fill a single section.
+//
+// Parameters:
+//   - val: JSON data as byte slice
+//   - dst: Non-nil pointer to the value to populate
+//
+// Returns an error if dst is not a non-nil pointer or decoding fails.
+//
+// Example:
+//
+//	var db Database
+//	err := codec.DecodeInto([]byte(`{"host": "localhost"}`), &db)
+func (c *Codec[T]) DecodeInto(val []byte, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("decode destination must be a non-nil pointer, got %T", dst)
+	}
+	if val == nil {
+		return nil
+	}
+	ast, err := c.parse(val)
 	if err != nil {
 		return err
 	}
-	return nil
+	return c.nodeToValue(ast, rv.Elem(), "")
+}
+
+// parse tokenizes val, checks it against the decode limits and builds its
+// AST.
+func (c *Codec[T]) parse(val []byte) (ASTNode, error) {
+	tokens, err := Tokenize(val)
+	if err != nil {
+		return nil, err
+	}
+	err = checkLimits(tokens, c.decodeOption())
+	if err != nil {
+		return nil, err
+	}
+	if c.decodeOption().StrictJSON {
+		if err := checkControlChars(val); err != nil {
+			return nil, err
+		}
+	}
+	return Parser(tokens)
 }
@@ -2,10 +2,16 @@
 package json
 
 import (
+	"bytes"
 	"fmt"
+	"math"
+	"reflect"
+	"strings"
 	"testing"
 
 	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+	"github.com/ahyalfan/gathuk/option"
+	"github.com/ahyalfan/gathuk/shared"
 )
 
 type MyStruct struct {
@@ -91,3 +97,901 @@ func TestCodec(t *testing.T) {
 		fmt.Println(string(b))
 	})
 }
+
+type BigID struct {
+	ID    int64   `config:"id"`
+	Count uint32  `config:"count"`
+	Ratio float64 `config:"ratio"`
+}
+
+func TestCodecInteger(t *testing.T) {
+	t.Run("Test 1: int64 near max round trip", func(t *testing.T) {
+		cdc := Codec[BigID]{}
+
+		var got BigID
+		err := cdc.Decode([]byte(`{"id": 9223372036854775806, "count": 42, "ratio": 1.5}`), &got)
+		customtests.OK(t, err)
+		customtests.Equals(t, BigID{ID: math.MaxInt64 - 1, Count: 42, Ratio: 1.5}, got)
+
+		b, err := cdc.Encode(got)
+		customtests.OK(t, err)
+		customtests.Assert(t, strings.Contains(string(b), `"id": 9223372036854775806`), "integer not encoded exactly: %s", b)
+
+		var again BigID
+		err = cdc.Decode(b, &again)
+		customtests.OK(t, err)
+		customtests.Equals(t, got, again)
+	})
+
+	t.Run("Test 2: tokenizer tags integers and floats", func(t *testing.T) {
+		tokens, err := Tokenize([]byte(`[1, -2, 1.5, 1e3, 99999999999999999999]`))
+		customtests.OK(t, err)
+
+		var types []int
+		for _, token := range tokens {
+			if token.Type == Integer || token.Type == Number {
+				types = append(types, token.Type)
+			}
+		}
+		customtests.Equals(t, []int{Integer, Integer, Number, Number, Number}, types)
+	})
+
+	t.Run("Test 3: scientific notation and negative integers", func(t *testing.T) {
+		cdc := Codec[BigID]{}
+
+		var got BigID
+		err := cdc.Decode([]byte(`{"id": -5, "count": 1e3}`), &got)
+		customtests.OK(t, err)
+		customtests.Equals(t, BigID{ID: -5, Count: 1000}, got)
+	})
+
+	t.Run("Test 4: fractional number into integer field", func(t *testing.T) {
+		cdc := Codec[BigID]{}
+
+		var got BigID
+		err := cdc.Decode([]byte(`{"id": 1.5}`), &got)
+		customtests.Assert(t, err != nil, "expected fractional error")
+		customtests.Assert(t, strings.Contains(err.Error(), "at id"), "missing path in %v", err)
+		customtests.Assert(t, strings.Contains(err.Error(), "fractional"), "unexpected error %v", err)
+	})
+
+	t.Run("Test 5: overflowing exponent", func(t *testing.T) {
+		cdc := Codec[BigID]{}
+
+		var got BigID
+		err := cdc.Decode([]byte(`{"id": 1e21}`), &got)
+		customtests.Assert(t, err != nil, "expected overflow error")
+		customtests.Assert(t, strings.Contains(err.Error(), "overflows int64"), "unexpected error %v", err)
+
+		err = cdc.Decode([]byte(`{"count": 1e10}`), &got)
+		customtests.Assert(t, err != nil, "expected overflow error")
+		customtests.Assert(t, strings.Contains(err.Error(), "overflows uint32"), "unexpected error %v", err)
+	})
+
+	t.Run("Test 6: integers beyond int64 stay exact as uint64", func(t *testing.T) {
+		type Big struct {
+			Max uint64 `config:"max"`
+			Any any    `config:"any"`
+			ID  int64  `config:"id"`
+		}
+		cdc := Codec[Big]{}
+
+		var got Big
+		err := cdc.Decode([]byte(`{"max": 18446744073709551615, "any": 9223372036854775808}`), &got)
+		customtests.OK(t, err)
+		customtests.Equals(t, Big{Max: math.MaxUint64, Any: uint64(math.MaxInt64 + 1)}, got)
+
+		b, err := cdc.Encode(got)
+		customtests.OK(t, err)
+		customtests.Assert(t, strings.Contains(string(b), `"max": 18446744073709551615`), "integer not encoded exactly: %s", b)
+
+		err = cdc.Decode([]byte(`{"id": 9223372036854775808}`), &got)
+		customtests.Assert(t, err != nil, "expected overflow error")
+		customtests.Assert(t, strings.Contains(err.Error(), "overflows int64"), "unexpected error %v", err)
+	})
+}
+
+type Credentials struct {
+	User     string `config:"user"`
+	Password string `config:"password,secret"`
+}
+
+type SecretConfig struct {
+	APIKey   string      `config:"api_key,secret"`
+	Host     string      `config:"host"`
+	Database Credentials `config:"db"`
+}
+
+func TestCodecSecrets(t *testing.T) {
+	val := SecretConfig{
+		APIKey:   "key",
+		Host:     "localhost",
+		Database: Credentials{User: "admin", Password: "hunter2"},
+	}
+
+	t.Run("Test 1: secrets are excluded recursively", func(t *testing.T) {
+		cdc := Codec[SecretConfig]{}
+		cdc.ApplyEncodeOption(&option.EncodeOption{ExcludeSecrets: true})
+		got, err := cdc.Encode(val)
+
+		customtests.OK(t, err)
+		customtests.Assert(t, !strings.Contains(string(got), "api_key"), "unexpected api_key in %s", got)
+		customtests.Assert(t, !strings.Contains(string(got), "password"), "unexpected password in %s", got)
+		customtests.Assert(t, strings.Contains(string(got), `"user": "admin"`), "missing user in %s", got)
+	})
+
+	t.Run("Test 2: secrets are redacted recursively", func(t *testing.T) {
+		cdc := Codec[SecretConfig]{}
+		cdc.ApplyEncodeOption(&option.EncodeOption{RedactSecrets: true})
+		got, err := cdc.Encode(val)
+
+		customtests.OK(t, err)
+		customtests.Assert(t, strings.Contains(string(got), `"api_key": "***"`), "api_key not redacted in %s", got)
+		customtests.Assert(t, strings.Contains(string(got), `"password": "***"`), "password not redacted in %s", got)
+		customtests.Assert(t, strings.Contains(string(got), `"user": "admin"`), "missing user in %s", got)
+	})
+
+	t.Run("Test 3: secrets are still decoded", func(t *testing.T) {
+		cdc := Codec[SecretConfig]{}
+		var got SecretConfig
+		err := cdc.Decode([]byte(`{"api_key": "key", "host": "localhost", "db": {"user": "admin", "password": "hunter2"}}`), &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, val, got)
+	})
+}
+
+func TestCodecCaseInsensitiveKeys(t *testing.T) {
+	data := []byte(`{"HOST": "localhost", "Db": {"USER": "admin"}}`)
+
+	t.Run("Test 1: exact matching by default", func(t *testing.T) {
+		cdc := Codec[SecretConfig]{}
+		var got SecretConfig
+		err := cdc.Decode(data, &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, SecretConfig{}, got)
+	})
+
+	t.Run("Test 2: mismatched casing matches when enabled", func(t *testing.T) {
+		cdc := Codec[SecretConfig]{}
+		cdc.ApplyDecodeOption(&option.DecodeOption{CaseInsensitiveKeys: true})
+		var got SecretConfig
+		err := cdc.Decode(data, &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, SecretConfig{Host: "localhost", Database: Credentials{User: "admin"}}, got)
+	})
+
+	t.Run("Test 3: exact match wins over folded keys", func(t *testing.T) {
+		cdc := Codec[SecretConfig]{}
+		cdc.ApplyDecodeOption(&option.DecodeOption{CaseInsensitiveKeys: true})
+		var got SecretConfig
+		err := cdc.Decode([]byte(`{"HOST": "upper", "host": "exact"}`), &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, "exact", got.Host)
+	})
+}
+
+func TestCodecMaxValueLen(t *testing.T) {
+	t.Run("Test 1: value under the limit", func(t *testing.T) {
+		cdc := Codec[SecretConfig]{}
+		cdc.ApplyDecodeOption(&option.DecodeOption{MaxValueLen: 9})
+		var got SecretConfig
+		err := cdc.Decode([]byte(`{"host": "localhost"}`), &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, "localhost", got.Host)
+	})
+
+	t.Run("Test 2: value over the limit", func(t *testing.T) {
+		cdc := Codec[SecretConfig]{}
+		cdc.ApplyDecodeOption(&option.DecodeOption{MaxValueLen: 8})
+		var got SecretConfig
+		err := cdc.Decode([]byte(`{"host": "localhost"}`), &got)
+
+		customtests.Assert(t, err != nil, "expected max value length error")
+	})
+}
+
+type CommonConfig struct {
+	Host string `config:"host"`
+	Port int    `config:"port"`
+}
+
+type EmbeddedConfig struct {
+	CommonConfig
+	Name string `config:"name"`
+}
+
+type InlineConfig struct {
+	Common CommonConfig `config:",inline"`
+	Name   string       `config:"name"`
+}
+
+func TestCodecEmbedded(t *testing.T) {
+	t.Run("Test 1: embedded fields are promoted on decode", func(t *testing.T) {
+		cdc := Codec[EmbeddedConfig]{}
+		var got EmbeddedConfig
+		err := cdc.Decode([]byte(`{"host": "localhost", "port": 8080, "name": "app"}`), &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, EmbeddedConfig{CommonConfig: CommonConfig{Host: "localhost", Port: 8080}, Name: "app"}, got)
+	})
+
+	t.Run("Test 2: embedded fields are promoted on encode", func(t *testing.T) {
+		cdc := Codec[EmbeddedConfig]{}
+		got, err := cdc.Encode(EmbeddedConfig{CommonConfig: CommonConfig{Host: "localhost"}, Name: "app"})
+
+		customtests.OK(t, err)
+		customtests.Assert(t, strings.Contains(string(got), `"host": "localhost"`), "missing host in %s", got)
+		customtests.Assert(t, !strings.Contains(string(got), "common_config"), "unexpected nesting in %s", got)
+	})
+
+	t.Run("Test 3: inline field round trips without nesting", func(t *testing.T) {
+		cdc := Codec[InlineConfig]{}
+		want := InlineConfig{Common: CommonConfig{Host: "localhost", Port: 8080}, Name: "app"}
+
+		b, err := cdc.Encode(want)
+		customtests.OK(t, err)
+		customtests.Assert(t, strings.Contains(string(b), `"host": "localhost"`), "missing host in %s", b)
+		customtests.Assert(t, !strings.Contains(string(b), "common"), "unexpected nesting in %s", b)
+
+		var got InlineConfig
+		err = cdc.Decode([]byte(`{"host": "localhost", "port": 8080, "name": "app", "common": {"host": "ignored"}}`), &got)
+		customtests.OK(t, err)
+		customtests.Equals(t, want, got)
+	})
+}
+
+func TestCodecMaxKeys(t *testing.T) {
+	data := []byte(`{"host": "localhost", "db": {"user": "admin", "password": "x"}}`)
+
+	t.Run("Test 1: under the limit", func(t *testing.T) {
+		cdc := Codec[SecretConfig]{}
+		cdc.ApplyDecodeOption(&option.DecodeOption{MaxKeys: 4})
+		var got SecretConfig
+		err := cdc.Decode(data, &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, "admin", got.Database.User)
+	})
+
+	t.Run("Test 2: nested keys count towards the limit", func(t *testing.T) {
+		cdc := Codec[SecretConfig]{}
+		cdc.ApplyDecodeOption(&option.DecodeOption{MaxKeys: 3})
+		var got SecretConfig
+		err := cdc.Decode(data, &got)
+
+		customtests.Assert(t, err != nil, "expected max keys error")
+	})
+}
+
+type Recipients struct {
+	To    []string `config:"to"`
+	Ports []int    `config:"ports"`
+}
+
+func TestCodecSingleValueAsSlice(t *testing.T) {
+	t.Run("Test 1: scalar becomes a one-element slice", func(t *testing.T) {
+		cdc := Codec[Recipients]{}
+		cdc.ApplyDecodeOption(&option.DecodeOption{SingleValueAsSlice: true})
+		var got Recipients
+		err := cdc.Decode([]byte(`{"to": "a@example.com", "ports": 8080}`), &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, Recipients{To: []string{"a@example.com"}, Ports: []int{8080}}, got)
+	})
+
+	t.Run("Test 2: arrays decode as usual", func(t *testing.T) {
+		cdc := Codec[Recipients]{}
+		cdc.ApplyDecodeOption(&option.DecodeOption{SingleValueAsSlice: true})
+		var got Recipients
+		err := cdc.Decode([]byte(`{"to": ["a@example.com", "b@example.com"], "ports": [80, 443]}`), &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, Recipients{To: []string{"a@example.com", "b@example.com"}, Ports: []int{80, 443}}, got)
+	})
+
+	t.Run("Test 3: scalar into slice fails without the option", func(t *testing.T) {
+		cdc := Codec[Recipients]{}
+		var got Recipients
+		err := cdc.Decode([]byte(`{"to": "a@example.com"}`), &got)
+
+		customtests.Assert(t, err != nil, "expected type error")
+	})
+}
+
+type Asset struct {
+	Name string `config:"name"`
+	Logo []byte `config:"logo,gz64"`
+}
+
+func TestCodecGz64(t *testing.T) {
+	blob := bytes.Repeat([]byte{0x89, 'P', 'N', 'G', 0x00}, 50)
+
+	t.Run("Test 1: round trip", func(t *testing.T) {
+		cdc := Codec[Asset]{}
+		b, err := cdc.Encode(Asset{Name: "app", Logo: blob})
+		customtests.OK(t, err)
+		customtests.Assert(t, strings.Contains(string(b), `"logo": "gz64:`), "missing gz64 value in %s", b)
+
+		var got Asset
+		err = cdc.Decode(b, &got)
+		customtests.OK(t, err)
+		customtests.Equals(t, Asset{Name: "app", Logo: blob}, got)
+	})
+
+	t.Run("Test 2: invalid blob", func(t *testing.T) {
+		cdc := Codec[Asset]{}
+		var got Asset
+		err := cdc.Decode([]byte(`{"logo": "gz64:aGVsbG8="}`), &got)
+
+		customtests.Assert(t, err != nil, "expected gz64 error")
+		customtests.Assert(t, strings.Contains(err.Error(), "at logo"), "missing path in %v", err)
+	})
+
+	t.Run("Test 3: only gz64 fields are decompressed", func(t *testing.T) {
+		type Raw struct {
+			Logo []byte `config:"logo"`
+		}
+		cdc := Codec[Raw]{}
+		var got Raw
+		err := cdc.Decode([]byte(`{"logo": "`+shared.EncodeGz64(blob)+`"}`), &got)
+		customtests.Assert(t, err != nil, "expected untagged field to reject the gz64 string")
+	})
+
+	t.Run("Test 4: decompressed size is capped by MaxValueLen", func(t *testing.T) {
+		cdc := Codec[Asset]{}
+		cdc.ApplyDecodeOption(&option.DecodeOption{MaxValueLen: 1000})
+		var got Asset
+		err := cdc.Decode([]byte(`{"logo": "`+shared.EncodeGz64(bytes.Repeat([]byte("a"), 2000))+`"}`), &got)
+		customtests.Assert(t, err != nil, "expected size limit error")
+	})
+}
+
+func TestCodecStrictTypes(t *testing.T) {
+	t.Run("Test 1: numeric string is converted by default", func(t *testing.T) {
+		cdc := Codec[BigID]{}
+		var got BigID
+		err := cdc.Decode([]byte(`{"id": "8080"}`), &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, int64(8080), got.ID)
+	})
+
+	t.Run("Test 2: numeric string is rejected with strict types", func(t *testing.T) {
+		cdc := Codec[BigID]{}
+		cdc.ApplyDecodeOption(&option.DecodeOption{StrictTypes: true})
+		var got BigID
+		err := cdc.Decode([]byte(`{"id": "8080"}`), &got)
+
+		customtests.Assert(t, err != nil, "expected type mismatch error")
+		customtests.Assert(t, strings.Contains(err.Error(), "type mismatch"), "unexpected error %v", err)
+		customtests.Assert(t, strings.Contains(err.Error(), "at id"), "missing path in %v", err)
+	})
+
+	t.Run("Test 3: matching types decode with strict types", func(t *testing.T) {
+		cdc := Codec[Item]{}
+		cdc.ApplyDecodeOption(&option.DecodeOption{StrictTypes: true})
+		var got Item
+		err := cdc.Decode([]byte(`{"id": "8080", "name": "gathuk"}`), &got)
+		customtests.OK(t, err)
+		customtests.Equals(t, Item{ID: "8080", Name: "gathuk"}, got)
+
+		idCdc := Codec[BigID]{}
+		idCdc.ApplyDecodeOption(&option.DecodeOption{StrictTypes: true})
+		var id BigID
+		err = idCdc.Decode([]byte(`{"id": 8080}`), &id)
+		customtests.OK(t, err)
+		customtests.Equals(t, int64(8080), id.ID)
+	})
+
+	t.Run("Test 4: number into string field is rejected", func(t *testing.T) {
+		cdc := Codec[Item]{}
+		cdc.ApplyDecodeOption(&option.DecodeOption{StrictTypes: true})
+		var got Item
+		err := cdc.Decode([]byte(`{"id": 8080}`), &got)
+
+		customtests.Assert(t, err != nil, "expected type mismatch error")
+	})
+}
+
+type Port uint16
+
+type Listener struct {
+	Port Port `config:"port"`
+}
+
+func TestCodecNamedNumericTypes(t *testing.T) {
+	t.Run("Test 1: decode named uint16", func(t *testing.T) {
+		cdc := Codec[Listener]{}
+		var got Listener
+		err := cdc.Decode([]byte(`{"port": 443}`), &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, Listener{Port: 443}, got)
+	})
+
+	t.Run("Test 2: overflowing named uint16", func(t *testing.T) {
+		cdc := Codec[Listener]{}
+		var got Listener
+		err := cdc.Decode([]byte(`{"port": 70000}`), &got)
+
+		customtests.Assert(t, err != nil, "expected overflow error")
+		customtests.Assert(t, strings.Contains(err.Error(), "overflows json.Port"), "unexpected error %v", err)
+
+		err = cdc.Decode([]byte(`{"port": "70000"}`), &got)
+		customtests.Assert(t, err != nil, "expected overflow error")
+	})
+
+	t.Run("Test 3: encode named uint16", func(t *testing.T) {
+		cdc := Codec[Listener]{}
+		got, err := cdc.Encode(Listener{Port: 443})
+
+		customtests.OK(t, err)
+		customtests.Assert(t, strings.Contains(string(got), `"port": 443`), "unexpected output %s", got)
+	})
+}
+
+type Ordered struct {
+	Debug bool   `config:"debug"`
+	Port  int    `config:"port" order:"2"`
+	Name  string `config:"name" order:"1"`
+	Host  string `config:"host" order:"2"`
+}
+
+func TestCodecOrder(t *testing.T) {
+	t.Run("Test 1: fields follow order tags", func(t *testing.T) {
+		cdc := Codec[Ordered]{}
+		got, err := cdc.Encode(Ordered{Debug: true, Port: 80, Name: "app", Host: "localhost"})
+
+		customtests.OK(t, err)
+		name := strings.Index(string(got), `"name"`)
+		port := strings.Index(string(got), `"port"`)
+		host := strings.Index(string(got), `"host"`)
+		debug := strings.Index(string(got), `"debug"`)
+		customtests.Assert(t, name < port && port < host && host < debug, "unexpected order %s", got)
+	})
+}
+
+func TestCodecStrictJSON(t *testing.T) {
+	input := []byte("{\"name\": \"a\tb\"}")
+
+	t.Run("Test 1: raw tab accepted by default", func(t *testing.T) {
+		cdc := Codec[Item]{}
+		var got Item
+		err := cdc.Decode(input, &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, "a\tb", got.Name)
+	})
+
+	t.Run("Test 2: raw tab rejected with StrictJSON", func(t *testing.T) {
+		cdc := Codec[Item]{}
+		cdc.ApplyDecodeOption(&option.DecodeOption{StrictJSON: true})
+		var got Item
+		err := cdc.Decode(input, &got)
+
+		customtests.Assert(t, err != nil, "expected control character error")
+		customtests.Assert(t, strings.Contains(err.Error(), "line 1 column 12"), "unexpected error %v", err)
+	})
+
+	t.Run("Test 3: whitespace outside strings allowed with StrictJSON", func(t *testing.T) {
+		cdc := Codec[Item]{}
+		cdc.ApplyDecodeOption(&option.DecodeOption{StrictJSON: true})
+		var got Item
+		err := cdc.Decode([]byte("{\n\t\"name\": \"ab\"\n}"), &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, "ab", got.Name)
+	})
+
+	t.Run("Test 4: control character on a later line", func(t *testing.T) {
+		cdc := Codec[Item]{}
+		cdc.ApplyDecodeOption(&option.DecodeOption{StrictJSON: true})
+		var got Item
+		err := cdc.Decode([]byte("{\n  \"id\": \"1\",\n  \"name\": \"a\tb\"\n}"), &got)
+
+		customtests.Assert(t, err != nil, "expected control character error")
+		customtests.Assert(t, strings.Contains(err.Error(), "line 3 column 13"), "unexpected error %v", err)
+	})
+}
+
+func TestCodecSecretResolver(t *testing.T) {
+	resolver := func(ref string) (string, error) {
+		if ref == "vault/db" {
+			return "s3cr3t", nil
+		}
+		return "", fmt.Errorf("secret %s not found", ref)
+	}
+
+	t.Run("Test 1: references are resolved", func(t *testing.T) {
+		cdc := Codec[SecretConfig]{}
+		cdc.ApplyDecodeOption(&option.DecodeOption{SecretResolver: resolver})
+		var got SecretConfig
+		err := cdc.Decode([]byte(`{"host": "localhost", "db": {"user": "app", "password": "secret://vault/db"}}`), &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, SecretConfig{
+			Host:     "localhost",
+			Database: Credentials{User: "app", Password: "s3cr3t"},
+		}, got)
+	})
+
+	t.Run("Test 2: resolver error names the path", func(t *testing.T) {
+		cdc := Codec[SecretConfig]{}
+		cdc.ApplyDecodeOption(&option.DecodeOption{SecretResolver: resolver})
+		var got SecretConfig
+		err := cdc.Decode([]byte(`{"db": {"password": "secret://vault/other"}}`), &got)
+
+		customtests.Assert(t, err != nil, "expected resolver error")
+		customtests.Assert(t, strings.Contains(err.Error(), "db.password"), "unexpected error %v", err)
+	})
+}
+
+func TestCodecSyntaxErrorPosition(t *testing.T) {
+	t.Run("Test 1: tokens record line and column", func(t *testing.T) {
+		tokens, err := Tokenize([]byte("{\n  \"name\": \"a\nb\",\n  \"id\": 7\n}"))
+		customtests.OK(t, err)
+
+		var got [][2]int
+		for _, token := range tokens {
+			got = append(got, [2]int{token.Line, token.Column})
+		}
+		customtests.Equals(t, [][2]int{{1, 1}, {2, 3}, {2, 9}, {2, 11}, {3, 3}, {4, 3}, {4, 7}, {4, 9}, {5, 1}}, got)
+	})
+
+	t.Run("Test 2: missing colon", func(t *testing.T) {
+		cdc := Codec[Item]{}
+		var got Item
+		err := cdc.Decode([]byte("{\n  \"id\": \"1\",\n  \"name\" \"x\"\n}"), &got)
+
+		customtests.Assert(t, err != nil, "expected syntax error")
+		customtests.Assert(t, strings.Contains(err.Error(), "expected : at line 3 column 10"), "unexpected error %v", err)
+	})
+
+	t.Run("Test 3: unexpected end of input", func(t *testing.T) {
+		cdc := Codec[Item]{}
+		var got Item
+		err := cdc.Decode([]byte(`{"id":`), &got)
+
+		customtests.Assert(t, err != nil, "expected syntax error")
+		customtests.Assert(t, strings.Contains(err.Error(), "end of input at line 1 column 7"), "unexpected error %v", err)
+	})
+
+	t.Run("Test 4: tokenizer errors", func(t *testing.T) {
+		cases := []struct {
+			input string
+			want  string
+		}{
+			{"{\n  \"id\": tru\n}", "unexpected character 't' at line 2 column 9"},
+			{"{\n  \"id\": 1.2.3\n}", "invalid number at line 2 column 9"},
+			{"{\n  \"id\": 1e\n}", "invalid number at line 2 column 9"},
+			{"{\n  \"id\": \"abc\n}", "unterminated string at line 2 column 9"},
+		}
+		for _, tc := range cases {
+			_, err := Tokenize([]byte(tc.input))
+			customtests.Assert(t, err != nil && strings.Contains(err.Error(), tc.want), "%q: expected %q, got %v", tc.input, tc.want, err)
+		}
+	})
+}
+
+func TestCodecUnterminatedContainers(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"Test 1: open brace only", `{`, "unterminated object"},
+		{"Test 2: object without closing brace", `{"id": "1"`, "unterminated object"},
+		{"Test 3: array without closing bracket", `[1,2`, "unterminated array"},
+		{"Test 4: missing value", `{"a":`, "unexpected end of input"},
+		{"Test 5: mismatched closer", `{"ids": [1, 2}`, `unexpected "}"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cdc := Codec[any]{}
+			var got any
+			err := cdc.Decode([]byte(tc.input), &got)
+
+			customtests.Assert(t, err != nil, "expected syntax error for %s", tc.input)
+			customtests.Assert(t, strings.Contains(err.Error(), tc.want), "unexpected error %v", err)
+		})
+	}
+}
+
+func TestCodecTopLevelArray(t *testing.T) {
+	t.Run("Test 1: array root into a slice", func(t *testing.T) {
+		cdc := Codec[[]Item]{}
+		var got []Item
+		err := cdc.Decode([]byte(`[{"id": "1", "name": "api"}, {"id": "2", "name": "worker"}]`), &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, []Item{{ID: "1", Name: "api"}, {ID: "2", Name: "worker"}}, got)
+	})
+
+	t.Run("Test 2: round trip", func(t *testing.T) {
+		cdc := Codec[[]Item]{}
+		bys, err := cdc.Encode([]Item{{ID: "1", Name: "api"}})
+		customtests.OK(t, err)
+
+		var got []Item
+		customtests.OK(t, cdc.Decode(bys, &got))
+		customtests.Equals(t, []Item{{ID: "1", Name: "api"}}, got)
+	})
+}
+
+type QuotedPort struct {
+	Port  int     `config:"port,string"`
+	Ratio float64 `json:"ratio,string"`
+	Debug bool    `config:"debug,string"`
+	Plain int     `config:"plain"`
+}
+
+func TestCodecStringOption(t *testing.T) {
+	t.Run("Test 1: numbers are encoded as strings", func(t *testing.T) {
+		cdc := Codec[QuotedPort]{}
+		got, err := cdc.Encode(QuotedPort{Port: 8080, Ratio: 0.5, Debug: true, Plain: 1})
+
+		customtests.OK(t, err)
+		for _, want := range []string{`"port":"8080"`, `"ratio":"0.5"`, `"debug":"true"`, `"plain":1`} {
+			customtests.Assert(t, strings.Contains(strings.ReplaceAll(string(got), " ", ""), want), "missing %s in %s", want, got)
+		}
+	})
+
+	t.Run("Test 2: quoted numbers decode with strict types", func(t *testing.T) {
+		cdc := Codec[QuotedPort]{}
+		cdc.ApplyDecodeOption(&option.DecodeOption{StrictTypes: true})
+		var got QuotedPort
+		err := cdc.Decode([]byte(`{"port": "8080", "ratio": "0.5", "debug": "true", "plain": 1}`), &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, QuotedPort{Port: 8080, Ratio: 0.5, Debug: true, Plain: 1}, got)
+	})
+
+	t.Run("Test 3: round trip", func(t *testing.T) {
+		cdc := Codec[QuotedPort]{}
+		want := QuotedPort{Port: 8080, Ratio: 1.25}
+		bys, err := cdc.Encode(want)
+		customtests.OK(t, err)
+
+		var got QuotedPort
+		customtests.OK(t, cdc.Decode(bys, &got))
+		customtests.Equals(t, want, got)
+	})
+
+	t.Run("Test 4: invalid quoted number", func(t *testing.T) {
+		cdc := Codec[QuotedPort]{}
+		var got QuotedPort
+		err := cdc.Decode([]byte(`{"port": "http"}`), &got)
+
+		customtests.Assert(t, err != nil, "expected conversion error")
+		customtests.Assert(t, strings.Contains(err.Error(), "at port"), "missing path in %v", err)
+	})
+}
+
+type NestedDB struct {
+	Host string `config:"host"`
+}
+
+type NestedTagConfig struct {
+	Database NestedDB `nested:"db"`
+	Cache    NestedDB `nested:"cache" json:"redis"`
+	Name     string   `nested:"ignored"`
+}
+
+func TestCodecNestedTag(t *testing.T) {
+	t.Run("Test 1: nested tag names the object on decode", func(t *testing.T) {
+		cdc := Codec[NestedTagConfig]{}
+		var got NestedTagConfig
+		err := cdc.Decode([]byte(`{"db": {"host": "localhost"}, "redis": {"host": "cache"}, "name": "app"}`), &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, "localhost", got.Database.Host)
+		customtests.Equals(t, "cache", got.Cache.Host)
+		customtests.Equals(t, "app", got.Name)
+	})
+
+	t.Run("Test 2: nested tag names the object on encode", func(t *testing.T) {
+		cdc := Codec[NestedTagConfig]{}
+		got, err := cdc.Encode(NestedTagConfig{Database: NestedDB{Host: "localhost"}})
+
+		customtests.OK(t, err)
+		customtests.Assert(t, strings.Contains(string(got), `"db": {`), "missing db object in %s", got)
+		customtests.Assert(t, !strings.Contains(string(got), `"database"`), "unexpected field name key in %s", got)
+	})
+}
+
+func TestCodecMapKeyConversion(t *testing.T) {
+	t.Run("Test 1: numeric keys", func(t *testing.T) {
+		cdc := Codec[map[int]string]{}
+		var got map[int]string
+		err := cdc.Decode([]byte(`{"80": "http", "443": "https"}`), &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, map[int]string{80: "http", 443: "https"}, got)
+	})
+
+	t.Run("Test 2: non-numeric key", func(t *testing.T) {
+		cdc := Codec[map[int]string]{}
+		var got map[int]string
+		err := cdc.Decode([]byte(`{"80": "http", "https": "443"}`), &got)
+
+		customtests.Assert(t, err != nil, "expected key conversion error")
+		customtests.Assert(t, strings.Contains(err.Error(), "https"), "error does not name the key: %v", err)
+	})
+
+	t.Run("Test 3: round trip", func(t *testing.T) {
+		cdc := Codec[map[uint16]bool]{}
+		bys, err := cdc.Encode(map[uint16]bool{80: true, 8080: false})
+		customtests.OK(t, err)
+
+		var got map[uint16]bool
+		customtests.OK(t, cdc.Decode(bys, &got))
+		customtests.Equals(t, map[uint16]bool{80: true, 8080: false}, got)
+	})
+}
+
+func TestCodecFloatFormat(t *testing.T) {
+	type Ratio struct {
+		Ratio float64 `config:"ratio"`
+	}
+
+	cases := []struct {
+		name string
+		eo   option.EncodeOption
+		want string
+	}{
+		{"Test 1: default", option.EncodeOption{}, `"ratio": 1e-07`},
+		{"Test 2: no exponent", option.EncodeOption{FloatFormat: 'f'}, `"ratio": 0.0000001`},
+		{"Test 3: exponent with precision", option.EncodeOption{FloatFormat: 'e', FloatPrecision: 2}, `"ratio": 1.00e-07`},
+		{"Test 4: fixed precision", option.EncodeOption{FloatFormat: 'f', FloatPrecision: 9}, `"ratio": 0.000000100`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cdc := Codec[Ratio]{}
+			cdc.ApplyEncodeOption(&tc.eo)
+			got, err := cdc.Encode(Ratio{Ratio: 1e-7})
+
+			customtests.OK(t, err)
+			customtests.Assert(t, strings.Contains(string(got), tc.want), "expected %s in %s", tc.want, got)
+
+			var back Ratio
+			customtests.OK(t, cdc.Decode(got, &back))
+			customtests.Equals(t, 1e-7, back.Ratio)
+		})
+	}
+}
+
+func TestCodecPointerStruct(t *testing.T) {
+	type Profile struct {
+		Name string `config:"name"`
+		Age  int    `config:"age"`
+	}
+	type Account struct {
+		ID      int      `config:"id"`
+		Profile *Profile `config:"profile"`
+		Limit   *int     `config:"limit"`
+	}
+
+	t.Run("Test 1: object allocates the pointer", func(t *testing.T) {
+		cdc := Codec[Account]{}
+		var got Account
+		err := cdc.Decode([]byte(`{"id": 1, "profile": {"name": "alice", "age": 30}, "limit": 5}`), &got)
+
+		customtests.OK(t, err)
+		customtests.Assert(t, got.Profile != nil, "profile not allocated")
+		customtests.Equals(t, Profile{Name: "alice", Age: 30}, *got.Profile)
+		customtests.Assert(t, got.Limit != nil && *got.Limit == 5, "limit not allocated: %v", got.Limit)
+	})
+
+	t.Run("Test 2: omitted object stays nil", func(t *testing.T) {
+		cdc := Codec[Account]{}
+		var got Account
+		err := cdc.Decode([]byte(`{"id": 2}`), &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, 2, got.ID)
+		customtests.Assert(t, got.Profile == nil, "profile allocated for an absent key: %v", got.Profile)
+	})
+
+	t.Run("Test 3: existing pointer is decoded in place", func(t *testing.T) {
+		cdc := Codec[Account]{}
+		got := Account{Profile: &Profile{Name: "alice", Age: 30}}
+		err := cdc.Decode([]byte(`{"profile": {"age": 31}}`), &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, Profile{Name: "alice", Age: 31}, *got.Profile)
+	})
+
+	t.Run("Test 4: null resets the pointer", func(t *testing.T) {
+		cdc := Codec[Account]{}
+		got := Account{Profile: &Profile{Name: "alice"}}
+		err := cdc.Decode([]byte(`{"profile": null}`), &got)
+
+		customtests.OK(t, err)
+		customtests.Assert(t, got.Profile == nil, "profile not reset: %v", got.Profile)
+	})
+}
+
+// Region provides its own default through shared.Defaulter.
+type Region string
+
+func (Region) GathukDefault() any { return Region("us-east-1") }
+
+func TestCodecTypeDefault(t *testing.T) {
+	type Bucket struct {
+		Name   string `config:"name"`
+		Region Region `config:"region"`
+	}
+
+	t.Run("Test 1: absent key is seeded", func(t *testing.T) {
+		cdc := Codec[Bucket]{}
+		var got Bucket
+		customtests.OK(t, cdc.Decode([]byte(`{"name": "logs"}`), &got))
+
+		customtests.Equals(t, Bucket{Name: "logs", Region: "us-east-1"}, got)
+	})
+
+	t.Run("Test 2: present key wins", func(t *testing.T) {
+		cdc := Codec[Bucket]{}
+		var got Bucket
+		customtests.OK(t, cdc.Decode([]byte(`{"region": "eu-west-1"}`), &got))
+
+		customtests.Equals(t, Region("eu-west-1"), got.Region)
+	})
+
+	t.Run("Test 3: pointer field is allocated", func(t *testing.T) {
+		type PointerBucket struct {
+			Name   string  `config:"name"`
+			Region *Region `config:"region"`
+		}
+		cdc := Codec[PointerBucket]{}
+		var got PointerBucket
+		customtests.OK(t, cdc.Decode([]byte(`{"name": "logs"}`), &got))
+
+		customtests.Assert(t, got.Region != nil && *got.Region == "us-east-1", "unexpected region %v", got.Region)
+	})
+}
+
+type Headers map[string]string
+
+type NamedMapConfig struct {
+	Headers Headers `config:"headers"`
+	Name    string  `config:"name"`
+}
+
+func TestCodecNamedMap(t *testing.T) {
+	t.Run("Test 1: decode keeps the named type", func(t *testing.T) {
+		cdc := Codec[NamedMapConfig]{}
+		var got NamedMapConfig
+		err := cdc.Decode([]byte(`{"name": "api", "headers": {"accept": "json", "user_agent": "gathuk"}}`), &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, Headers{"accept": "json", "user_agent": "gathuk"}, got.Headers)
+		customtests.Equals(t, "Headers", reflect.TypeOf(got.Headers).Name())
+	})
+
+	t.Run("Test 2: round trip", func(t *testing.T) {
+		cdc := Codec[NamedMapConfig]{}
+		want := NamedMapConfig{Headers: Headers{"accept": "json"}, Name: "api"}
+
+		b, err := cdc.Encode(want)
+		customtests.OK(t, err)
+
+		var got NamedMapConfig
+		customtests.OK(t, cdc.Decode(b, &got))
+		customtests.Equals(t, want, got)
+	})
+
+	t.Run("Test 3: top-level named map", func(t *testing.T) {
+		cdc := Codec[Headers]{}
+		var got Headers
+		err := cdc.Decode([]byte(`{"accept": "json"}`), &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, Headers{"accept": "json"}, got)
+	})
+}
@@ -3,6 +3,7 @@ package json
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
@@ -18,8 +19,9 @@ import (
 //   - Nested structs → ObjectNode
 //   - Slices/arrays → ArrayNode
 //   - Maps → ObjectNode
-//   - Primitive types → StringNode, NumberNode, BooleanNode
+//   - Primitive types → StringNode, IntegerNode, UnsignedNode, NumberNode, BooleanNode
 //   - Struct tags for custom field names
+//   - `secret` fields are skipped when EncodeOption.ExcludeSecrets is set
 //
 // Parameters:
 //   - value: Pointer to the struct to convert
@@ -37,7 +39,7 @@ import (
 //
 //	config := &Config{Port: 8080, Host: "localhost"}
 //	ast, err := codec.StructToAST(config)
-//	// ast: ObjectNode{Value: {"port": NumberNode{8080}, "host": StringNode{"localhost"}}}
+//	// ast: ObjectNode{Value: {"port": IntegerNode{8080}, "host": StringNode{"localhost"}}}
 func (c *Codec[T]) StructToAST(value *T) (ASTNode, error) {
 	if value == nil {
 		return NullNode{}, nil
@@ -76,6 +78,10 @@ func (c *Codec[T]) valueToNode(v reflect.Value, path string) (ASTNode, error) {
 		return c.valueToNode(v.Elem(), path)
 	}
 
+	if v.Type() == shared.OrderedMapType {
+		return c.orderedMapToNode(v.Interface().(shared.OrderedMap), path)
+	}
+
 	switch v.Kind() {
 	case reflect.Struct:
 		return c.structToNode(v, path)
@@ -93,10 +99,13 @@ func (c *Codec[T]) valueToNode(v reflect.Value, path string) (ASTNode, error) {
 		return BooleanNode{Value: v.Bool()}, nil
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return NumberNode{Value: float64(v.Int())}, nil
+		return IntegerNode{Value: v.Int()}, nil
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return NumberNode{Value: float64(v.Uint())}, nil
+		if u := v.Uint(); u <= math.MaxInt64 {
+			return IntegerNode{Value: int64(u)}, nil
+		}
+		return UnsignedNode{Value: v.Uint()}, nil
 
 	case reflect.Float32, reflect.Float64:
 		return NumberNode{Value: v.Float()}, nil
@@ -106,33 +115,93 @@ func (c *Codec[T]) valueToNode(v reflect.Value, path string) (ASTNode, error) {
 	}
 }
 
+// FieldKey returns the JSON object key a struct field maps to.
+//
+// The key is resolved in this order:
+//  1. The `config` tag
+//  2. The `json` tag
+//  3. The `nested` tag (struct and pointer-to-struct fields only), so a
+//     struct keyed for .env with `nested:"db"` reads the "db" object
+//  4. The field name converted to lower_snake_case
+//
+// Tag options after a comma (e.g. `json:"port,omitempty"`) are ignored.
+//
+// Parameters:
+//   - field: The struct field to resolve
+//
+// Returns:
+//   - string: The object key, or "" if the field is unexported or excluded with "-"
+//
+// Example:
+//
+//	type Config struct {
+//	    Port     int    `json:"server_port"` // FieldKey: "server_port"
+//	    LogLevel string                      // FieldKey: "log_level"
+//	    Database DB     `nested:"db"`         // FieldKey: "db"
+//	}
+func FieldKey(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+
+	tags := []string{string(shared.GetTagName()), "json"}
+	if isStructField(field) {
+		tags = append(tags, string(shared.GetTagNestedName()))
+	}
+	for _, tag := range tags {
+		value := field.Tag.Get(tag)
+		if value == "-" {
+			return ""
+		}
+		if name, _ := shared.ParseTag(value); name != "" {
+			return name
+		}
+	}
+	return utility.PascalToLowerSnakeCase(field.Name)
+}
+
 func (c *Codec[T]) structToNode(v reflect.Value, path string) (ASTNode, error) {
-	obj := make(map[string]ASTNode)
+	obj := ObjectNode{Value: make(map[string]ASTNode)}
+	if err := c.structFieldsToNodes(v, path, &obj, false); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// structFieldsToNodes converts the fields of a struct into nodes stored in obj.
+//
+// Fields of embedded structs without a tag are promoted into the same object.
+// A promoted field never replaces a key set by a field of the outer struct.
+// Keys are recorded in the order given by shared.FieldOrder, so the `order`
+// tag controls the order of the encoded object.
+//
+// Parameters:
+//   - v: The struct value
+//   - path: The path of the struct, used in error messages
+//   - obj: The object to store the nodes in
+//   - promoted: Whether v is an embedded struct being promoted
+//
+// Returns:
+//   - error: An error if a field cannot be converted
+func (c *Codec[T]) structFieldsToNodes(v reflect.Value, path string, obj *ObjectNode, promoted bool) error {
 	t := v.Type()
+	excludeSecrets := c.eo != nil && c.eo.ExcludeSecrets
+	redactSecrets := c.eo != nil && c.eo.RedactSecrets
 
-	for i := 0; i < v.NumField(); i++ {
+	for _, i := range shared.FieldOrder(t) {
 		field := t.Field(i)
-		if !field.IsExported() {
-			continue
-		}
-
-		jsonTag := field.Tag.Get(string(shared.GetTagName()))
-		if jsonTag == "-" {
+		if excludeSecrets && shared.IsSecret(field) {
 			continue
 		}
-		if jsonTag == "" {
-			jsonTag = field.Tag.Get("json")
-			if jsonTag == "-" {
-				continue
+		if isPromoted(field) {
+			if err := c.structFieldsToNodes(v.Field(i), path, obj, true); err != nil {
+				return err
 			}
+			continue
 		}
-
-		name := jsonTag
-		if idx := strings.Index(jsonTag, ","); idx != -1 {
-			name = jsonTag[:idx]
-		}
+		name := FieldKey(field)
 		if name == "" {
-			name = utility.PascalToLowerSnakeCase(field.Name)
+			continue
 		}
 
 		fieldPath := path + "." + name
@@ -140,14 +209,106 @@ func (c *Codec[T]) structToNode(v reflect.Value, path string) (ASTNode, error) {
 			fieldPath = name
 		}
 
+		if _, ok := obj.Value[name]; ok && promoted {
+			continue
+		}
+
+		if redactSecrets && shared.IsSecret(field) {
+			obj.set(name, StringNode{Value: shared.Redacted})
+			continue
+		}
+		if shared.IsGz64(field) {
+			obj.set(name, StringNode{Value: shared.EncodeGz64(v.Field(i).Bytes())})
+			continue
+		}
+
 		node, err := c.valueToNode(v.Field(i), fieldPath)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if isQuoted(field) {
+			node = c.quoteNode(node)
 		}
-		obj[name] = node
+		obj.set(name, node)
 	}
 
-	return ObjectNode{Value: obj}, nil
+	return nil
+}
+
+// set stores a node under key, appending key to Keys if it is new.
+func (obj *ObjectNode) set(key string, node ASTNode) {
+	if _, ok := obj.Value[key]; !ok {
+		obj.Keys = append(obj.Keys, key)
+	}
+	obj.Value[key] = node
+}
+
+// isPromoted reports whether the fields of an embedded struct field are
+// promoted to the parent object, i.e. the field has no `config`, `json` or
+// `nested` tag naming it.
+func isPromoted(field reflect.StructField) bool {
+	return shared.IsPromoted(field, string(shared.GetTagName()), "json", string(shared.GetTagNestedName()))
+}
+
+// isStructField reports whether a field holds a struct or a pointer to one
+// that maps to a JSON object, as opposed to a scalar struct like time.Time.
+func isStructField(field reflect.StructField) bool {
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !shared.IsScalarStruct(t)
+}
+
+// isQuoted reports whether a struct field has the `string` option in its
+// `config` or `json` tag, e.g. `config:"port,string"`. Like the option of
+// encoding/json, it stores a numeric or boolean field as a JSON string.
+func isQuoted(field reflect.StructField) bool {
+	for _, tag := range []string{string(shared.GetTagName()), "json"} {
+		if _, opts := shared.ParseTag(field.Tag.Get(tag)); opts.Contains("string") {
+			return true
+		}
+	}
+	return false
+}
+
+// quoteNode converts a numeric or boolean node into a StringNode holding its
+// JSON literal, e.g. IntegerNode{8080} into StringNode{"8080"}. Other nodes
+// are returned unchanged.
+func (c *Codec[T]) quoteNode(node ASTNode) ASTNode {
+	switch n := node.(type) {
+	case IntegerNode:
+		return StringNode{Value: strconv.FormatInt(n.Value, 10)}
+	case UnsignedNode:
+		return StringNode{Value: strconv.FormatUint(n.Value, 10)}
+	case NumberNode:
+		return StringNode{Value: c.formatFloat(n.Value)}
+	case BooleanNode:
+		return StringNode{Value: strconv.FormatBool(n.Value)}
+	}
+	return node
+}
+
+// orderedMapToNode converts an OrderedMap to an ObjectNode keeping its key
+// order.
+func (c *Codec[T]) orderedMapToNode(m shared.OrderedMap, path string) (ASTNode, error) {
+	obj := ObjectNode{Value: make(map[string]ASTNode, len(m))}
+	for _, kv := range m {
+		elemPath := path + "." + kv.Key
+		if path == "" {
+			elemPath = kv.Key
+		}
+
+		node, err := c.valueToNode(reflect.ValueOf(&kv.Value).Elem(), elemPath)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := obj.Value[kv.Key]; !ok {
+			obj.Keys = append(obj.Keys, kv.Key)
+		}
+		obj.Value[kv.Key] = node
+	}
+	return obj, nil
 }
 
 func (c *Codec[T]) sliceToNode(v reflect.Value, path string) (ASTNode, error) {
@@ -167,13 +328,13 @@ func (c *Codec[T]) sliceToNode(v reflect.Value, path string) (ASTNode, error) {
 }
 
 func (c *Codec[T]) mapToNode(v reflect.Value, path string) (ASTNode, error) {
-	if v.Type().Key().Kind() != reflect.String {
-		return nil, fmt.Errorf("map key must be string at %s, got %s", path, v.Type().Key().Kind())
+	if !shared.IsMapKeyType(v.Type().Key()) {
+		return nil, fmt.Errorf("map key must be a string, number or bool at %s, got %s", path, v.Type().Key().Kind())
 	}
 
 	obj := make(map[string]ASTNode)
 	for _, key := range v.MapKeys() {
-		keyStr := key.String()
+		keyStr := fmt.Sprint(key.Interface())
 		val := v.MapIndex(key)
 
 		elemPath := path + "." + keyStr
@@ -200,6 +361,10 @@ func (c *Codec[T]) mapToNode(v reflect.Value, path string) (ASTNode, error) {
 //   - Type conversions (string → int, etc.)
 //   - Struct tags for field mapping
 //
+// The root does not have to be an object: when T is a slice, a document
+// whose top level is an array, e.g. a list of server definitions, decodes
+// into it element by element.
+//
 // Parameters:
 //   - node: The AST node to convert
 //   - v: Pointer to the destination struct
@@ -233,6 +398,10 @@ func (c *Codec[T]) ASTToStruct(node ASTNode, v *T) error {
 // It handles the conversion of AST nodes to Go values with proper
 // type checking and conversion.
 //
+// Pointers are followed: a nil pointer is allocated before the node is
+// decoded into its element, and a non-nil one is decoded into in place, so
+// absent keys keep their values. A null sets the pointer to nil.
+//
 // Parameters:
 //   - node: The AST node to convert
 //   - v: The target reflect.Value
@@ -255,35 +424,74 @@ func (c *Codec[T]) nodeToValue(node ASTNode, v reflect.Value, path string) error
 		return nil
 	}
 
+	if v.Kind() == reflect.Ptr {
+		if _, ok := node.(NullNode); ok {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		ptr := v
+		if v.IsNil() {
+			ptr = reflect.New(v.Type().Elem())
+		}
+		if err := c.nodeToValue(node, ptr.Elem(), path); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	}
+
+	if v.Kind() == reflect.Slice && isScalar(node) && c.decodeOption().SingleValueAsSlice {
+		return c.mapArray(ArrayNode{Value: []ASTNode{node}}, v, path)
+	}
+
 	switch node := node.(type) {
 	case ObjectNode:
+		if v.Type() == shared.OrderedMapType {
+			m, err := c.toOrderedMap(node, path)
+			if err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(m))
+			return nil
+		}
+
 		switch v.Kind() {
 		case reflect.Struct:
 			return c.mapObject(node, v, path)
 		case reflect.Map:
 			return c.mapToMap(node, v, path)
 		default:
-			return c.newError(path, "expected struct or map, got %s", v.Kind())
+			return c.conversionError(path, "expected struct or map, got %s", v.Kind())
 		}
 
 	case ArrayNode:
 		if v.Kind() != reflect.Slice {
-			return c.newError(path, "expected slice, got %s", v.Kind())
+			return c.conversionError(path, "expected slice, got %s", v.Kind())
 		}
 		return c.mapArray(node, v, path)
 
 	case StringNode:
-		return c.stringValue(node.Value, v, path)
+		s, err := shared.ResolveSecret(node.Value, c.decodeOption().SecretResolver)
+		if err != nil {
+			return c.newError(path, "%w", err)
+		}
+		return c.stringValue(s, v, path)
 
 	case NumberNode:
 		return c.numberValue(node.Value, v, path)
 
+	case IntegerNode:
+		return c.integerValue(node.Value, v, path)
+
+	case UnsignedNode:
+		return c.unsignedValue(node.Value, v, path)
+
 	case BooleanNode:
 		if v.Kind() == reflect.Bool {
 			v.SetBool(node.Value)
 			return nil
 		}
-		return c.newError(path, "cannot unmarshal boolean into %s", v.Kind())
+		return c.conversionError(path, "cannot unmarshal boolean into %s", v.Kind())
 
 	case NullNode:
 		v.Set(reflect.Zero(v.Type()))
@@ -294,31 +502,34 @@ func (c *Codec[T]) nodeToValue(node ASTNode, v reflect.Value, path string) error
 	}
 }
 
+// isScalar reports whether node holds a single string, number or boolean.
+func isScalar(node ASTNode) bool {
+	switch node.(type) {
+	case StringNode, NumberNode, IntegerNode, UnsignedNode, BooleanNode:
+		return true
+	}
+	return false
+}
+
 func (c *Codec[T]) mapObject(node ObjectNode, v reflect.Value, path string) error {
+	var folded map[string]ASTNode
+	if c.decodeOption().CaseInsensitiveKeys {
+		folded = foldKeys(node)
+	}
+
 	t := v.Type()
 	for i := 0; i < v.NumField(); i++ {
 		field := t.Field(i)
-		if !field.IsExported() {
-			continue
-		}
-
-		jsonTag := field.Tag.Get(string(shared.GetTagName()))
-		if jsonTag == "-" {
-			continue
-		}
-		if jsonTag == "" {
-			jsonTag = field.Tag.Get("json")
-			if jsonTag == "-" {
-				continue
+		if isPromoted(field) {
+			if err := c.mapObject(node, v.Field(i), path); err != nil {
+				return err
 			}
+			continue
 		}
 
-		name := jsonTag
-		if idx := strings.Index(jsonTag, ","); idx != -1 {
-			name = jsonTag[:idx]
-		}
+		name := FieldKey(field)
 		if name == "" {
-			name = utility.PascalToLowerSnakeCase(field.Name)
+			continue
 		}
 
 		fieldPath := path + "." + name
@@ -326,19 +537,58 @@ func (c *Codec[T]) mapObject(node ObjectNode, v reflect.Value, path string) erro
 			fieldPath = name
 		}
 
-		if childNode, ok := node.Value[name]; ok {
-			fieldVal := v.Field(i)
-			if err := c.nodeToValue(childNode, fieldVal, fieldPath); err != nil {
+		childNode, ok := node.Value[name]
+		if !ok && folded != nil {
+			childNode, ok = folded[strings.ToLower(name)]
+		}
+		fieldVal := v.Field(i)
+		if !ok {
+			if err := shared.ApplyTypeDefault(fieldVal); err != nil {
+				return c.newError(fieldPath, "%w", err)
+			}
+			continue
+		}
+		if str, isStr := childNode.(StringNode); isStr && shared.IsGz64(field) {
+			if err := c.gz64Value(str.Value, fieldVal, fieldPath); err != nil {
+				return err
+			}
+			continue
+		}
+		if str, isStr := childNode.(StringNode); isStr && isQuoted(field) {
+			if err := c.parseString(str.Value, fieldVal, fieldPath); err != nil {
 				return err
 			}
+			continue
+		}
+		if err := c.nodeToValue(childNode, fieldVal, fieldPath); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
+// foldKeys builds a lookup of the object's values keyed by lowercased key,
+// used when DecodeOption.CaseInsensitiveKeys is set. When several keys fold to
+// the same name, the already lowercase key wins so matching stays
+// deterministic.
+func foldKeys(node ObjectNode) map[string]ASTNode {
+	folded := make(map[string]ASTNode, len(node.Value))
+	for key, child := range node.Value {
+		lower := strings.ToLower(key)
+		if _, ok := folded[lower]; ok && key != lower {
+			continue
+		}
+		folded[lower] = child
+	}
+	return folded
+}
+
+// mapToMap decodes an object into a map. Keys are parsed into the key type of
+// the map like `,string` values, so {"80": "http"} decodes into a
+// map[int]string; a key that does not parse is an error.
 func (c *Codec[T]) mapToMap(node ObjectNode, v reflect.Value, path string) error {
-	if v.Type().Key().Kind() != reflect.String {
-		return c.newError(path, "map key must be string, got %s", v.Type().Key())
+	if !shared.IsMapKeyType(v.Type().Key()) {
+		return c.newError(path, "map key must be a string, number or bool, got %s", v.Type().Key())
 	}
 
 	mapType := v.Type()
@@ -350,12 +600,17 @@ func (c *Codec[T]) mapToMap(node ObjectNode, v reflect.Value, path string) error
 			elemPath = key
 		}
 
+		keyValue := reflect.New(mapType.Key()).Elem()
+		if err := c.parseString(key, keyValue, elemPath); err != nil {
+			return err
+		}
+
 		elemValue := reflect.New(mapType.Elem()).Elem()
 		if err := c.nodeToValue(childNode, elemValue, elemPath); err != nil {
 			return err
 		}
 
-		newMap.SetMapIndex(reflect.ValueOf(key), elemValue)
+		newMap.SetMapIndex(keyValue, elemValue)
 	}
 
 	v.Set(newMap)
@@ -381,15 +636,62 @@ func (c *Codec[T]) mapArray(node ArrayNode, v reflect.Value, path string) error
 	return nil
 }
 
+// gz64Value sets a []byte field marked with the `gz64` option. A "gz64:"
+// value is base64 decoded and gunzipped, up to DecodeOption.MaxValueLen bytes
+// (or shared.MaxGz64Len when unset); other strings are set by stringValue.
+func (c Codec[T]) gz64Value(s string, v reflect.Value, path string) error {
+	data, ok, err := shared.DecodeGz64(s, c.decodeOption().MaxValueLen)
+	if err != nil {
+		return c.conversionError(path, "%w", err)
+	}
+	if !ok {
+		return c.stringValue(s, v, path)
+	}
+	v.SetBytes(data)
+	return nil
+}
+
 func (c Codec[T]) stringValue(s string, v reflect.Value, path string) error {
+	strict := c.decodeOption().StrictTypes
+
 	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+		return nil
+	}
+
+	if strict {
+		return c.conversionError(path, "type mismatch: cannot unmarshal string %q into %s with strict types", s, v.Type())
+	}
+	return c.parseString(s, v, path)
+}
+
+// parseString parses s into a numeric or boolean value, as written for a
+// field with the `,string` option or read leniently from a JSON string.
+//
+// Parameters:
+//   - s: The string content
+//   - v: The value to set
+//   - path: Current path in the struct (for error reporting)
+//
+// Returns:
+//   - error: An error if s cannot be parsed into the kind of v
+func (c Codec[T]) parseString(s string, v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(v.Type().Elem())
+		if err := c.parseString(s, elem.Elem(), path); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
 	case reflect.String:
 		v.SetString(s)
 		return nil
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
 			if v.OverflowInt(i) {
-				return c.newError(path, "string %q overflows %s", s, v.Type())
+				return c.conversionError(path, "string %q overflows %s", s, v.Type())
 			}
 			v.SetInt(i)
 			return nil
@@ -397,7 +699,7 @@ func (c Codec[T]) stringValue(s string, v reflect.Value, path string) error {
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		if u, err := strconv.ParseUint(s, 10, 64); err == nil {
 			if v.OverflowUint(u) {
-				return c.newError(path, "string %q overflows %s", s, v.Type())
+				return c.conversionError(path, "string %q overflows %s", s, v.Type())
 			}
 			v.SetUint(u)
 			return nil
@@ -405,7 +707,7 @@ func (c Codec[T]) stringValue(s string, v reflect.Value, path string) error {
 	case reflect.Float32, reflect.Float64:
 		if f, err := strconv.ParseFloat(s, 64); err == nil {
 			if v.OverflowFloat(f) {
-				return c.newError(path, "string %q overflows %s", s, v.Type())
+				return c.conversionError(path, "string %q overflows %s", s, v.Type())
 			}
 			v.SetFloat(f)
 			return nil
@@ -416,42 +718,92 @@ func (c Codec[T]) stringValue(s string, v reflect.Value, path string) error {
 			return nil
 		}
 	}
-	return c.newError(path, "cannot unmarshal string %q into %s", s, v.Type())
+	return c.conversionError(path, "cannot unmarshal string %q into %s", s, v.Type())
 }
 
 func (c *Codec[T]) numberValue(f float64, v reflect.Value, path string) error {
 	switch v.Kind() {
 	case reflect.Float32, reflect.Float64:
 		if v.OverflowFloat(f) {
-			return c.newError(path, "number %g overflows %s", f, v.Type())
+			return c.conversionError(path, "number %g overflows %s", f, v.Type())
 		}
 		v.SetFloat(f)
 		return nil
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		i := int64(f)
-		if float64(i) != f {
-			return c.newError(path, "number %g cannot be converted to integer", f)
+		if f != math.Trunc(f) {
+			return c.conversionError(path, "number %g has a fractional part and cannot be assigned to %s", f, v.Type())
 		}
+		// Range-check before converting: float-to-int conversion of an
+		// out-of-range value is implementation-defined in Go.
+		if f < math.MinInt64 || f >= math.MaxInt64 {
+			return c.conversionError(path, "number %g overflows %s", f, v.Type())
+		}
+		i := int64(f)
 		if v.OverflowInt(i) {
-			return c.newError(path, "number %g overflows %s", f, v.Type())
+			return c.conversionError(path, "number %g overflows %s", f, v.Type())
 		}
 		v.SetInt(i)
 		return nil
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		if f < 0 {
-			return c.newError(path, "negative number %g cannot be assigned to unsigned type", f)
+			return c.conversionError(path, "negative number %g cannot be assigned to unsigned type", f)
+		}
+		if f != math.Trunc(f) {
+			return c.conversionError(path, "number %g has a fractional part and cannot be assigned to %s", f, v.Type())
+		}
+		if f >= math.MaxUint64 {
+			return c.conversionError(path, "number %g overflows %s", f, v.Type())
 		}
 		u := uint64(f)
-		if float64(u) != f {
-			return c.newError(path, "number %g cannot be converted to unsigned integer", f)
+		if v.OverflowUint(u) {
+			return c.conversionError(path, "number %g overflows %s", f, v.Type())
+		}
+		v.SetUint(u)
+		return nil
+	}
+	return c.conversionError(path, "cannot unmarshal number %g into %s", f, v.Type())
+}
+
+func (c *Codec[T]) integerValue(i int64, v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.OverflowInt(i) {
+			return c.conversionError(path, "number %d overflows %s", i, v.Type())
+		}
+		v.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if i < 0 {
+			return c.conversionError(path, "negative number %d cannot be assigned to unsigned type", i)
+		}
+		if v.OverflowUint(uint64(i)) {
+			return c.conversionError(path, "number %d overflows %s", i, v.Type())
 		}
+		v.SetUint(uint64(i))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(float64(i))
+		return nil
+	}
+	return c.conversionError(path, "cannot unmarshal number %d into %s", i, v.Type())
+}
+
+func (c *Codec[T]) unsignedValue(u uint64, v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		if v.OverflowUint(u) {
-			return c.newError(path, "number %g overflows %s", f, v.Type())
+			return c.conversionError(path, "number %d overflows %s", u, v.Type())
 		}
 		v.SetUint(u)
 		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// an UnsignedNode is always beyond the int64 range
+		return c.conversionError(path, "number %d overflows %s", u, v.Type())
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(float64(u))
+		return nil
 	}
-	return c.newError(path, "cannot unmarshal number %g into %s", f, v.Type())
+	return c.conversionError(path, "cannot unmarshal number %d into %s", u, v.Type())
 }
 
 // toNative converts an AST node to native Go types for interface{}.
@@ -460,11 +812,17 @@ func (c *Codec[T]) numberValue(f float64, v reflect.Value, path string) error {
 // It converts AST nodes to appropriate Go types:
 //   - StringNode → string
 //   - NumberNode → float64
+//   - IntegerNode → int64
+//   - UnsignedNode → uint64
 //   - BooleanNode → bool
 //   - NullNode → nil
 //   - ArrayNode → []interface{}
 //   - ObjectNode → map[string]interface{}
 //
+// The .env codec infers the same types from its untyped values (see
+// shared.InferScalar), so an `any` field holds the same dynamic type
+// whichever format it is loaded from.
+//
 // Parameters:
 //   - node: The AST node to convert
 //   - path: Current path (for error reporting)
@@ -475,9 +833,17 @@ func (c *Codec[T]) numberValue(f float64, v reflect.Value, path string) error {
 func (c *Codec[T]) toNative(node ASTNode, path string) (interface{}, error) {
 	switch n := node.(type) {
 	case StringNode:
-		return n.Value, nil
+		s, err := shared.ResolveSecret(n.Value, c.decodeOption().SecretResolver)
+		if err != nil {
+			return nil, c.newError(path, "%w", err)
+		}
+		return s, nil
 	case NumberNode:
 		return n.Value, nil
+	case IntegerNode:
+		return n.Value, nil
+	case UnsignedNode:
+		return n.Value, nil
 	case BooleanNode:
 		return n.Value, nil
 	case NullNode:
@@ -511,6 +877,51 @@ func (c *Codec[T]) toNative(node ASTNode, path string) (interface{}, error) {
 	}
 }
 
+// toOrderedMap converts an ObjectNode to an OrderedMap in document order.
+//
+// Values are converted like toNative, except that nested objects become
+// OrderedMap values too, so their order is preserved as well.
+//
+// Parameters:
+//   - node: The object node to convert
+//   - path: Current path (for error reporting)
+//
+// Returns:
+//   - shared.OrderedMap: The converted map
+//   - error: An error if conversion fails
+func (c *Codec[T]) toOrderedMap(node ObjectNode, path string) (shared.OrderedMap, error) {
+	keys := node.Keys
+	if len(keys) != len(node.Value) {
+		keys = make([]string, 0, len(node.Value))
+		for k := range node.Value {
+			keys = append(keys, k)
+		}
+	}
+
+	m := make(shared.OrderedMap, 0, len(keys))
+	for _, k := range keys {
+		elemPath := path + "." + k
+		if path == "" {
+			elemPath = k
+		}
+
+		var (
+			val any
+			err error
+		)
+		if obj, ok := node.Value[k].(ObjectNode); ok {
+			val, err = c.toOrderedMap(obj, elemPath)
+		} else {
+			val, err = c.toNative(node.Value[k], elemPath)
+		}
+		if err != nil {
+			return nil, err
+		}
+		m = append(m, shared.KeyValue{Key: k, Value: val})
+	}
+	return m, nil
+}
+
 // newError creates a formatted error with path information.
 //
 // This helper method is used throughout the mapper to create
@@ -530,3 +941,9 @@ func (c *Codec[T]) newError(path, format string, args ...interface{}) error {
 	}
 	return fmt.Errorf("ast unmarshal error: "+format, args...)
 }
+
+// conversionError is like newError for a value that cannot be converted to
+// its target type; the error matches shared.ErrTypeConversion.
+func (c *Codec[T]) conversionError(path, format string, args ...any) error {
+	return shared.WrapError(c.newError(path, format, args...), shared.ErrTypeConversion)
+}
@@ -19,6 +19,9 @@ import (
 //   - parseArray: Handles JSON arrays
 //   - Primitive values: Handled directly in parseValue
 //
+// Syntax errors name the line and column of the offending token, e.g.
+// "expected : at line 4 column 12".
+//
 // Parameters:
 //   - tokens: Sequence of tokens from the tokenizer
 //
@@ -59,7 +62,7 @@ func Parser(tokens []Token) (ASTNode, error) {
 //   - error: An error if parsing fails
 func parseValue(current *int, tokens []Token) (ASTNode, error) {
 	if *current >= len(tokens) {
-		return nil, fmt.Errorf("unexpected end of input")
+		return nil, fmt.Errorf("unexpected end of input at %s", position(tokens, *current))
 	}
 
 	token := tokens[*current]
@@ -72,6 +75,13 @@ func parseValue(current *int, tokens []Token) (ASTNode, error) {
 		num, _ := strconv.ParseFloat(string(token.Value), 64)
 		*current++
 		return NumberNode{Value: num}, nil
+	case Integer:
+		*current++
+		if num, err := strconv.ParseInt(string(token.Value), 10, 64); err == nil {
+			return IntegerNode{Value: num}, nil
+		}
+		num, _ := strconv.ParseUint(string(token.Value), 10, 64)
+		return UnsignedNode{Value: num}, nil
 	case True:
 		*current++
 		return BooleanNode{Value: true}, nil
@@ -86,7 +96,7 @@ func parseValue(current *int, tokens []Token) (ASTNode, error) {
 	case BracketOpen:
 		return parseArray(current, tokens)
 	default:
-		return nil, fmt.Errorf("invalid token type: %v", token.Type)
+		return nil, fmt.Errorf("unexpected %q at %s", token.Value, position(tokens, *current))
 	}
 }
 
@@ -94,6 +104,9 @@ func parseValue(current *int, tokens []Token) (ASTNode, error) {
 //
 // Expected token sequence: { "key" : value , "key" : value ... }
 //
+// Running out of tokens before the closing brace is an error, so truncated
+// input such as {"a": 1 is not mistaken for a complete object.
+//
 // Parameters:
 //   - current: Pointer to current position in token stream
 //   - tokens: Complete token stream
@@ -112,14 +125,14 @@ func parseObject(current *int, tokens []Token) (ASTNode, error) {
 		currToken := tokens[*current]
 
 		if currToken.Type != String {
-			return nil, fmt.Errorf("expected string key in object, got: %v", currToken.Type)
+			return nil, fmt.Errorf("expected string key in object at %s, got: %q", position(tokens, *current), currToken.Value)
 		}
 
 		key := currToken.Value
 		*current++
 
 		if *current >= len(tokens) || tokens[*current].Type != Colon {
-			return nil, fmt.Errorf("expected : in key value pair, got: %v", currToken.Type)
+			return nil, fmt.Errorf("expected : at %s", position(tokens, *current))
 		}
 
 		*current++
@@ -128,6 +141,9 @@ func parseObject(current *int, tokens []Token) (ASTNode, error) {
 		if err != nil {
 			return nil, err
 		}
+		if _, ok := node.Value[string(key)]; !ok {
+			node.Keys = append(node.Keys, string(key))
+		}
 		node.Value[string(key)] = value
 
 		if *current < len(tokens) && tokens[*current].Type == Comma {
@@ -135,8 +151,11 @@ func parseObject(current *int, tokens []Token) (ASTNode, error) {
 		}
 	}
 
-	if *current < len(tokens) && tokens[*current].Type != BraceClose {
-		return nil, fmt.Errorf("expected closing brace, got: %v", tokens[*current].Type)
+	if *current >= len(tokens) {
+		return nil, fmt.Errorf("unterminated object: unexpected end of input at %s, expected }", position(tokens, *current))
+	}
+	if tokens[*current].Type != BraceClose {
+		return nil, fmt.Errorf("expected closing brace at %s, got: %q", position(tokens, *current), tokens[*current].Value)
 	}
 
 	*current++
@@ -148,6 +167,8 @@ func parseObject(current *int, tokens []Token) (ASTNode, error) {
 //
 // Expected token sequence: [ value , value , value ... ]
 //
+// Running out of tokens before the closing bracket is an error.
+//
 // Parameters:
 //   - current: Pointer to current position in token stream
 //   - tokens: Complete token stream
@@ -172,9 +193,29 @@ func parseArray(current *int, tokens []Token) (ASTNode, error) {
 		}
 	}
 
-	if *current < len(tokens) && tokens[*current].Type != BracketClose {
-		return nil, fmt.Errorf("expected closing bracket, got: %v", tokens[*current].Type)
+	if *current >= len(tokens) {
+		return nil, fmt.Errorf("unterminated array: unexpected end of input at %s, expected ]", position(tokens, *current))
+	}
+	if tokens[*current].Type != BracketClose {
+		return nil, fmt.Errorf("expected closing bracket at %s, got: %q", position(tokens, *current), tokens[*current].Value)
 	}
 	*current++
 	return node, nil
 }
+
+// position describes where tokens[i] starts, e.g. "line 4 column 12". Past
+// the end of the stream, the position right after the last token is used.
+func position(tokens []Token, i int) string {
+	if len(tokens) == 0 {
+		return "line 1 column 1"
+	}
+	if i >= len(tokens) {
+		last := tokens[len(tokens)-1]
+		width := len(last.Value)
+		if last.Type == String {
+			width += 2 // quotes
+		}
+		return fmt.Sprintf("line %d column %d", last.Line, last.Column+width)
+	}
+	return fmt.Sprintf("line %d column %d", tokens[i].Line, tokens[i].Column)
+}
@@ -5,6 +5,9 @@ import (
 	"bytes"
 	"fmt"
 	"strconv"
+
+	"github.com/ahyalfan/gathuk/option"
+	"github.com/ahyalfan/gathuk/shared"
 )
 
 // escapeStringByte escapes special characters in a byte slice for JSON.
@@ -98,6 +101,10 @@ func (c *Codec[T]) serializeNode(buf *bytes.Buffer, node ASTNode, depth int) err
 		c.serializeString(buf, n)
 	case NumberNode:
 		c.serializeNumber(buf, n)
+	case IntegerNode:
+		c.serializeInteger(buf, n)
+	case UnsignedNode:
+		c.serializeUnsigned(buf, n)
 	case BooleanNode:
 		c.serializeBoolean(buf, n)
 	case NullNode:
@@ -113,6 +120,8 @@ func (c *Codec[T]) serializeNode(buf *bytes.Buffer, node ASTNode, depth int) err
 //
 // Output format: {"key": value, "key": value}
 //
+// Keys are written in the order of obj.Keys when it covers every key.
+//
 // Parameters:
 //   - buf: The buffer to write to
 //   - obj: The ObjectNode to serialize
@@ -123,8 +132,17 @@ func (c *Codec[T]) serializeNode(buf *bytes.Buffer, node ASTNode, depth int) err
 func (c *Codec[T]) serializeObject(buf *bytes.Buffer, obj ObjectNode, depth int) error {
 	buf.WriteByte('{')
 
+	keys := obj.Keys
+	if len(keys) != len(obj.Value) {
+		keys = make([]string, 0, len(obj.Value))
+		for key := range obj.Value {
+			keys = append(keys, key)
+		}
+	}
+
 	first := true
-	for key, value := range obj.Value {
+	for _, key := range keys {
+		value := obj.Value[key]
 		if !first {
 			buf.WriteByte(',')
 		}
@@ -206,7 +224,47 @@ func (c *Codec[T]) serializeString(buf *bytes.Buffer, str StringNode) error {
 // Returns:
 //   - error: An error if serialization fails
 func (c *Codec[T]) serializeNumber(buf *bytes.Buffer, num NumberNode) error {
-	buf.WriteString(strconv.FormatFloat(num.Value, 'g', -1, 64))
+	buf.WriteString(c.formatFloat(num.Value))
+	return nil
+}
+
+// formatFloat formats f as a JSON number using the FloatFormat and
+// FloatPrecision encode options, defaulting to the shortest 'g' form.
+func (c *Codec[T]) formatFloat(f float64) string {
+	eo := c.eo
+	if eo == nil {
+		eo = &option.EncodeOption{}
+	}
+	return shared.FormatFloat(f, eo.FloatFormat, 'g', eo.FloatPrecision, 64)
+}
+
+// serializeInteger serializes an IntegerNode to JSON format.
+//
+// The value is written in base 10 without going through float64, so large
+// integers are encoded exactly.
+//
+// Parameters:
+//   - buf: The buffer to write to
+//   - num: The IntegerNode to serialize
+//
+// Returns:
+//   - error: An error if serialization fails
+func (c *Codec[T]) serializeInteger(buf *bytes.Buffer, num IntegerNode) error {
+	buf.WriteString(strconv.FormatInt(num.Value, 10))
+	return nil
+}
+
+// serializeUnsigned serializes an UnsignedNode to JSON format, exactly like
+// serializeInteger.
+//
+// Parameters:
+//   - buf: The buffer to write to
+//   - num: The UnsignedNode to serialize
+//
+// Returns:
+//   - error: An error if serialization fails
+func (c *Codec[T]) serializeUnsigned(buf *bytes.Buffer, num UnsignedNode) error {
+	buf.WriteString(strconv.FormatUint(num.Value, 10))
 	return nil
 }
 
@@ -17,6 +17,7 @@ const (
 	True
 	False
 	Null
+	Integer
 )
 
 // Token represents a single lexical token from JSON input.
@@ -16,7 +16,14 @@ import (
 //
 // Supported tokens:
 //   - Structural: { } [ ] : ,
-//   - Literals: "string", 123, true, false, null
+//   - Literals: "string", 123, 1.5, true, false, null
+//
+// Numbers without a fraction or exponent that fit in an int64 or a uint64 are
+// emitted as Integer tokens; all other numbers are emitted as Number tokens.
+//
+// Every token records the 1-based line and column where it starts, so the
+// parser can report the position of syntax errors. Errors of the tokenizer
+// itself name the line and column of the offending token the same way.
 //
 // The tokenizer handles:
 //   - Whitespace (spaces, tabs, newlines) - ignored
@@ -43,16 +50,25 @@ func Tokenize(input []byte) ([]Token, error) {
 		tokens      []Token
 		char        byte
 		inputLength = len(input)
+		line        = 1
+		lineStart   = 0
 	)
 
 	for current < len(input) {
 		char = input[current]
 
 		if unicode.IsSpace(rune(char)) {
+			if char == '\n' {
+				line++
+				lineStart = current + 1
+			}
 			current++
 			continue
 		}
 
+		first := len(tokens)
+		tokenLine, tokenColumn := line, current-lineStart+1
+
 		switch char {
 		case '{':
 			v := Token{Type: BraceOpen, Value: []byte{char}}
@@ -89,15 +105,21 @@ func Tokenize(input []byte) ([]Token, error) {
 			temp := input[start:current]
 
 			if current >= inputLength {
-				return nil, fmt.Errorf("untermineted string: %s", string(temp))
+				return nil, fmt.Errorf("unterminated string at line %d column %d: %s", tokenLine, tokenColumn, string(temp))
 			}
 
 			v := Token{Type: String, Value: temp}
 			tokens = append(tokens, v)
 
+			// raw newlines inside the string move the position too
+			if i := bytes.LastIndexByte(temp, '\n'); i >= 0 {
+				line += bytes.Count(temp, []byte{'\n'})
+				lineStart = start + i + 1
+			}
+
 			current++
 		default:
-			rest := input[current : current+8] // get prefix
+			rest := input[current:min(current+8, inputLength)] // get prefix
 
 			if bytes.HasPrefix(rest, []byte("true")) {
 				v := Token{Type: True, Value: []byte("true")}
@@ -130,13 +152,13 @@ func Tokenize(input []byte) ([]Token, error) {
 						}
 					} else if te == '.' {
 						if hasDot || hasExp {
-							return nil, fmt.Errorf("invalid number: multiple dots or dot after exponent at position %d", current)
+							return nil, fmt.Errorf("invalid number at line %d column %d: multiple dots or dot after exponent", tokenLine, tokenColumn)
 						}
 						hasDot = true
 						current++
 					} else if te == 'e' || te == 'E' {
 						if hasExp {
-							return nil, fmt.Errorf("invalid number: multiple exponents at position %d", current)
+							return nil, fmt.Errorf("invalid number at line %d column %d: multiple exponents", tokenLine, tokenColumn)
 						}
 						hasExp = true
 						current++
@@ -155,17 +177,30 @@ func Tokenize(input []byte) ([]Token, error) {
 				num := input[start:current]
 
 				if hasExp && expDigits == 0 {
-					return nil, fmt.Errorf("invalid number: exponent missing digits in '%s'", num)
+					return nil, fmt.Errorf("invalid number at line %d column %d: exponent missing digits in '%s'", tokenLine, tokenColumn, num)
 				}
 				if _, err := strconv.ParseFloat(string(num), 64); err != nil {
-					return nil, fmt.Errorf("invalid number: %s", string(num))
+					return nil, fmt.Errorf("invalid number at line %d column %d: %s", tokenLine, tokenColumn, string(num))
+				}
+				tokenType := Number
+				if !hasDot && !hasExp {
+					if _, err := strconv.ParseInt(string(num), 10, 64); err == nil {
+						tokenType = Integer
+					} else if _, err := strconv.ParseUint(string(num), 10, 64); err == nil {
+						tokenType = Integer
+					}
 				}
-				tokens = append(tokens, Token{Type: Number, Value: num})
+				tokens = append(tokens, Token{Type: tokenType, Value: num})
 			} else {
-				return nil, fmt.Errorf("unexpected character: %c, position: %d", char, current)
+				return nil, fmt.Errorf("unexpected character %q at line %d column %d", char, tokenLine, tokenColumn)
 			}
 
 		}
+
+		if len(tokens) > first {
+			tokens[first].Line = tokenLine
+			tokens[first].Column = tokenColumn
+		}
 	}
 
 	return tokens, nil
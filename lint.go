@@ -0,0 +1,211 @@
+// Package gathuk
+package gathuk
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/ahyalfan/gathuk/internal/encoding/dotenv"
+	"github.com/ahyalfan/gathuk/internal/encoding/json"
+	"github.com/ahyalfan/gathuk/option"
+	"github.com/ahyalfan/gathuk/shared"
+)
+
+// LintFile reports the keys of a configuration file that do not map to any
+// field of T, such as settings left behind after a field was renamed or
+// removed. Running it in CI keeps config files free of stale keys.
+//
+// The file is first loaded like with LoadConfigFiles, so decoding errors are
+// reported as well. Keys are read and matched with the decode options of the
+// format, so a KeyPrefix or NestedSeparator is taken into account. Keys absorbed by a catch-all field, and keys below a map
+// or any field, count as used. Only the built-in "env" and "json" formats can
+// be linted; JSON keys are reported as dotted paths such as "database.host".
+//
+// Parameters:
+//   - path: Path to the configuration file
+//
+// Returns:
+//   - unused: The unused keys in file order, or nil if every key is used
+//   - err: An error if the file cannot be loaded or its format is not supported
+//
+// Example:
+//
+//	unused, err := gathuk.NewGathuk[Config]().LintFile("config.env")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, key := range unused {
+//	    fmt.Println("unused key:", key)
+//	}
+func (g *Gathuk[T]) LintFile(path string) (unused []string, err error) {
+	format, _ := fileFormat(path)
+	if format != "env" && format != "json" {
+		return nil, fmt.Errorf("lint %q: unsupported format %q: %w", path, format, ErrFormatNotSupported)
+	}
+
+	var val T
+	if err := g.loadFile(context.Background(), path, &val); err != nil {
+		return nil, err
+	}
+
+	bys, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// read the keys like the real decode, but without the OS environment
+	do := *g.decodeOption(format)
+	do.AutomaticEnv = false
+	do.PersistToOSEnv = false
+
+	keys, err := fileKeys(bys, format, &do)
+	if err != nil {
+		return nil, fmt.Errorf("lint %q: %w", path, err)
+	}
+
+	t := reflect.TypeOf(&g.value).Elem()
+	fields := typeFields(t)
+	var catchAll []string
+	if format == "env" {
+		catchAll = catchAllPrefixes(t, "", envSeparator(&do), map[reflect.Type]bool{t: true})
+	}
+
+	for _, key := range keys {
+		if !keyUsed(key, format, fields, catchAll, &do) {
+			unused = append(unused, key)
+		}
+	}
+	return unused, nil
+}
+
+// fileKeys returns the keys declared in configuration content read with do,
+// in order. JSON objects are flattened into dotted key paths.
+func fileKeys(bys []byte, format string, do *option.DecodeOption) ([]string, error) {
+	var m shared.OrderedMap
+	switch format {
+	case "env":
+		cdc := dotenv.Codec[shared.OrderedMap]{}
+		cdc.ApplyDecodeOption(do)
+		if err := cdc.Decode(bys, &m); err != nil {
+			return nil, err
+		}
+	case "json":
+		cdc := json.Codec[shared.OrderedMap]{}
+		cdc.ApplyDecodeOption(do)
+		if err := cdc.Decode(bys, &m); err != nil {
+			return nil, err
+		}
+	}
+
+	var keys []string
+	for _, kv := range m {
+		keys = appendKeyPaths(keys, kv.Key, kv.Value)
+	}
+	return keys, nil
+}
+
+// appendKeyPaths appends key to keys, or the dotted paths of its leaves when
+// value is an object.
+func appendKeyPaths(keys []string, key string, value any) []string {
+	switch v := value.(type) {
+	case shared.OrderedMap:
+		for _, kv := range v {
+			keys = appendKeyPaths(keys, key+"."+kv.Key, kv.Value)
+		}
+	case map[string]any:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			keys = appendKeyPaths(keys, key+"."+name, v[name])
+		}
+	default:
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// keyUsed reports whether a file key maps to one of fields, or falls under
+// one of the catch-all prefixes. .env keys of fields are built with the
+// KeyPrefix and NestedSeparator of do.
+func keyUsed(key, format string, fields []fieldInfo, catchAll []string, do *option.DecodeOption) bool {
+	sep := "."
+	root := ""
+	if format == "env" {
+		key = strings.ToUpper(key)
+		sep = envSeparator(do)
+		root = envKeyPrefix(do)
+	}
+
+	for _, f := range fields {
+		fieldKey := f.JSONKey
+		if format == "env" {
+			fieldKey = ""
+			if f.EnvKey != "" {
+				fieldKey = root + strings.ToUpper(strings.Join(f.EnvPath, sep))
+			}
+		}
+		if fieldKey == "" {
+			continue
+		}
+		if key == fieldKey {
+			return true
+		}
+		kind := f.Field.Type.Kind()
+		if (kind == reflect.Map || kind == reflect.Interface) && strings.HasPrefix(key, fieldKey+sep) {
+			return true
+		}
+	}
+
+	for _, prefix := range catchAll {
+		if prefix == "" && strings.HasPrefix(key, root) || prefix != "" && strings.HasPrefix(key, root+prefix+sep) {
+			return true
+		}
+	}
+	return false
+}
+
+// envSeparator returns the separator joining nested .env keys read with do,
+// DecodeOption.NestedSeparator or "_" by default.
+func envSeparator(do *option.DecodeOption) string {
+	if do.NestedSeparator != "" {
+		return do.NestedSeparator
+	}
+	return "_"
+}
+
+// catchAllPrefixes returns the upper case .env prefixes, joined with sep and
+// without the KeyPrefix, of the structs in t holding a catch-all field; ""
+// stands for the root struct. Struct types in seen, the ones being walked,
+// are not entered again.
+func catchAllPrefixes(t reflect.Type, prefix, sep string, seen map[reflect.Type]bool) []string {
+	var prefixes []string
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		if shared.IsCatchAll(structField) {
+			prefixes = append(prefixes, prefix)
+			continue
+		}
+		if shared.IsPromoted(structField, string(shared.GetTagNestedName()), string(shared.GetTagName()), "env", "json") {
+			prefixes = append(prefixes, catchAllPrefixes(structField.Type, prefix, sep, seen)...)
+			continue
+		}
+
+		nested := structField.Type
+		if nested.Kind() == reflect.Ptr {
+			nested = nested.Elem()
+		}
+		key := dotenv.FieldKey(structField)
+		if !structField.IsExported() || key == "" || nested.Kind() != reflect.Struct || shared.IsScalarStruct(nested) || seen[nested] {
+			continue
+		}
+		seen[nested] = true
+		prefixes = append(prefixes, catchAllPrefixes(nested, strings.ToUpper(joinKey(prefix, key, sep)), sep, seen)...)
+		delete(seen, nested)
+	}
+	return prefixes
+}
@@ -0,0 +1,99 @@
+// Package gathuk
+package gathuk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+	"github.com/ahyalfan/gathuk/option"
+)
+
+func TestLintFile(t *testing.T) {
+	t.Run("Test 1: obsolete env key", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.env")
+		customtests.OK(t, os.WriteFile(path, []byte("SIMPLE_E=1\nDB_USER=app\nDB_LEGACY_HOST=old\nDEBUG_C=true\n"), 0o644))
+
+		unused, err := NewGathuk[Simple2]().LintFile(path)
+		customtests.OK(t, err)
+		customtests.Equals(t, []string{"DB_LEGACY_HOST"}, unused)
+	})
+
+	t.Run("Test 2: obsolete json key", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		customtests.OK(t, os.WriteFile(path, []byte(`{"simple_e": 1, "db": {"user": "app", "legacy_host": "old"}}`), 0o644))
+
+		unused, err := NewGathuk[Simple2]().LintFile(path)
+		customtests.OK(t, err)
+		customtests.Equals(t, []string{"db.legacy_host"}, unused)
+	})
+
+	t.Run("Test 3: every key used", func(t *testing.T) {
+		type Config struct {
+			Host   string            `config:"host"`
+			Labels map[string]string `config:"labels"`
+		}
+		path := filepath.Join(t.TempDir(), "config.json")
+		customtests.OK(t, os.WriteFile(path, []byte(`{"host": "localhost", "labels": {"team": "core"}}`), 0o644))
+
+		unused, err := NewGathuk[Config]().LintFile(path)
+		customtests.OK(t, err)
+		customtests.Equals(t, []string(nil), unused)
+	})
+
+	t.Run("Test 4: catch-all absorbs keys", func(t *testing.T) {
+		type Config struct {
+			Host  string            `config:"host"`
+			Extra map[string]string `config:",catchall"`
+		}
+		path := filepath.Join(t.TempDir(), "config.env")
+		customtests.OK(t, os.WriteFile(path, []byte("HOST=localhost\nFEATURE_X=on\n"), 0o644))
+
+		unused, err := NewGathuk[Config]().LintFile(path)
+		customtests.OK(t, err)
+		customtests.Equals(t, []string(nil), unused)
+	})
+}
+
+type LintCycleA struct {
+	Name string
+	B    *LintCycleB
+}
+
+type LintCycleB struct {
+	Extra map[string]string `config:",catchall"`
+	A     *LintCycleA
+}
+
+func TestLintRecursiveStruct(t *testing.T) {
+	t.Run("Test 1: mutually recursive types", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.env")
+		customtests.OK(t, os.WriteFile(path, []byte("NAME=a\nSTALE=1"), 0o644))
+
+		unused, err := NewGathuk[LintCycleA]().LintFile(path)
+		customtests.OK(t, err)
+		customtests.Equals(t, []string{"STALE"}, unused)
+	})
+
+	t.Run("Test 5: prefixed env keys", func(t *testing.T) {
+		type Config struct {
+			Port int
+			DB   struct {
+				Host string
+			} `config:"db"`
+		}
+		path := filepath.Join(t.TempDir(), "config.env")
+		customtests.OK(t, os.WriteFile(path, []byte("MYAPP_PORT=1\nMYAPP_DB__HOST=x\n; MYAPP_OLD=1\nMYAPP_DB_HOST=y\nPORT=2\n"), 0o644))
+
+		gt := NewGathuk[Config]()
+		gt.SetDecodeOption("env", &option.DecodeOption{KeyPrefix: "MYAPP_", NestedSeparator: "__", CommentPrefixes: []string{";"}})
+		unused, err := gt.LintFile(path)
+		customtests.OK(t, err)
+		customtests.Equals(t, []string{"MYAPP_DB_HOST", "PORT"}, unused)
+
+		customtests.OK(t, gt.LoadConfigFiles(path))
+		customtests.Equals(t, 1, gt.GetConfig().Port)
+		customtests.Equals(t, "x", gt.GetConfig().DB.Host)
+	})
+}
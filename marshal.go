@@ -0,0 +1,275 @@
+// Package gathuk
+package gathuk
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/ahyalfan/gathuk/internal/encoding/json"
+
+	"github.com/ahyalfan/gathuk/option"
+)
+
+// Unmarshal decodes data in the given format into dst, without creating a
+// Gathuk instance.
+//
+// A throwaway codec is taken from a default codec registry, so only the
+// built-in formats ("env", "json") are supported. Like encoding/json, keys
+// absent from data leave the existing values of dst untouched. No merging,
+// validation or environment binding happens unless enabled through opts.
+//
+// Parameters:
+//   - data: The encoded configuration
+//   - format: The format of data (e.g., "env", "json")
+//   - dst: Pointer to the value to populate
+//   - opts: Optional decode options; only the first one is used
+//
+// Returns an error if the format is not supported or decoding fails.
+//
+// Example:
+//
+//	var cfg Config
+//	err := gathuk.Unmarshal([]byte("PORT=8080\nHOST=localhost"), "env", &cfg)
+//
+//	// With options
+//	err = gathuk.Unmarshal(data, "env", &cfg, &option.DecodeOption{AutomaticEnv: true})
+func Unmarshal[T any](data []byte, format string, dst *T, opts ...*option.DecodeOption) error {
+	dc, err := NewDefaultCodecRegister[T]().Decoder(format)
+	if err != nil {
+		return err
+	}
+
+	do := &option.DecodeOption{}
+	if len(opts) > 0 && opts[0] != nil {
+		do = opts[0]
+	}
+	dc.ApplyDecodeOption(do)
+
+	return dc.Decode(data, dst)
+}
+
+// Marshal encodes v in the given format, without creating a Gathuk instance.
+//
+// A throwaway codec is taken from a default codec registry, so only the
+// built-in formats ("env", "json") are supported.
+//
+// Parameters:
+//   - v: The value to encode
+//   - format: The output format (e.g., "env", "json")
+//   - opts: Optional encode options; only the first one is used
+//
+// Returns the encoded bytes, or an error if the format is not supported or
+// encoding fails.
+//
+// Example:
+//
+//	data, err := gathuk.Marshal(Config{Port: 8080}, "json")
+//
+//	// Leave secret fields out
+//	data, err = gathuk.Marshal(cfg, "env", &option.EncodeOption{ExcludeSecrets: true})
+func Marshal[T any](v T, format string, opts ...*option.EncodeOption) ([]byte, error) {
+	ec, err := NewDefaultCodecRegister[T]().Encoder(format)
+	if err != nil {
+		return nil, err
+	}
+
+	eo := &option.EncodeOption{}
+	if len(opts) > 0 && opts[0] != nil {
+		eo = opts[0]
+	}
+	ec.ApplyEncodeOption(eo)
+
+	return ec.Encode(v)
+}
+
+// DecodeBytes decodes data in the given format into a new value of T and
+// returns it, leaving the loaded configuration untouched.
+//
+// Unlike Unmarshal, it runs the pipeline of g: the codec registry and decode
+// options of g are used, registered migrations are applied, fields start at
+// their `default` tag values, and the result goes through the transforms
+// registered with AddTransform and is validated. Comments found in
+// data are not recorded.
+//
+// Parameters:
+//   - data: The encoded configuration
+//   - format: The format of data (e.g., "env", "json")
+//
+// Returns the decoded value, or an error if the format is not supported,
+// decoding fails or the value does not pass validation.
+//
+// Example:
+//
+//	cfg, err := gt.DecodeBytes([]byte(`{"port": 8080}`), "json")
+//	// gt.GetConfig() is unchanged
+func (g *Gathuk[T]) DecodeBytes(data []byte, format string) (T, error) {
+	var zero T
+
+	dc, err := g.decoder(format)
+	if err != nil {
+		return zero, err
+	}
+
+	data, err = g.migrate(data, format)
+	if err != nil {
+		return zero, err
+	}
+
+	val := g.defaultValue()
+	if err := dc.Decode(data, &val); err != nil {
+		return zero, err
+	}
+	if err := g.afterLoad(&val); err != nil {
+		return zero, err
+	}
+	return val, nil
+}
+
+// BindStruct decodes the configuration read from src into dst, which may
+// point to any type, not just T, using the codec registered for format and
+// the decode options of g. This allows binding a single section of a
+// configuration to its own struct. The loaded configuration is unchanged and
+// no defaults, transforms or validation are applied.
+//
+// Parameters:
+//   - dst: Non-nil pointer to the value to populate
+//   - format: The format of src (e.g., "env", "json")
+//   - src: io.Reader containing the configuration data
+//
+// Returns an error if the format is not supported, its codec cannot decode
+// into other types, dst is not a non-nil pointer or decoding fails.
+//
+// Example:
+//
+//	var db DatabaseConfig
+//	err := gt.BindStruct(&db, "env", strings.NewReader("DB_HOST=localhost"))
+func (g *Gathuk[T]) BindStruct(dst any, format string, src io.Reader) error {
+	dc, err := g.decoder(format)
+	if err != nil {
+		return err
+	}
+	into, ok := dc.(interface {
+		DecodeInto(buf []byte, dst any) error
+	})
+	if !ok {
+		return fmt.Errorf("codec for format %q cannot decode into %T", format, dst)
+	}
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return err
+	}
+	return into.DecodeInto(data, dst)
+}
+
+// EncodeBytes encodes v in the given format with the codec registry and
+// encode options of g, and returns the result instead of writing it.
+//
+// Parameters:
+//   - format: The output format (e.g., "env", "json")
+//   - v: The value to encode
+//
+// Returns the encoded bytes, or an error if the format is not supported or
+// encoding fails.
+//
+// Example:
+//
+//	data, err := gt.EncodeBytes("env", gt.GetConfig())
+func (g *Gathuk[T]) EncodeBytes(format string, v T) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := g.write(&buf, format, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ToMap returns the current configuration as nested map[string]any values,
+// e.g. to feed it into a template or a structured logger.
+//
+// Keys are the JSON keys of the fields, nested structs become nested maps,
+// slices become []any, and scalars become string, int64, float64 or bool,
+// just like loading JSON into a map[string]any would produce. The encode
+// options set for "json" with SetEncodeOption are applied, so ExcludeSecrets
+// leaves secret fields out.
+//
+// Returns the converted configuration, or an error if the configuration
+// cannot be represented as a JSON object.
+//
+// Example:
+//
+//	m, err := gt.ToMap()
+//	// m["db"].(map[string]any)["host"] == "localhost"
+func (g *Gathuk[T]) ToMap() (map[string]any, error) {
+	return g.jsonCodec().ToMap(g.GetConfig())
+}
+
+// jsonCodec returns a clone of the "json" codec of the registry carrying the
+// options set for "json", or the global options where none are set. The
+// registered codec itself is never modified. A plain json codec is used when
+// "json" is handled by a custom codec.
+func (g *Gathuk[T]) jsonCodec() *json.Codec[T] {
+	cdc := &json.Codec[T]{}
+	if c, err := g.CodecRegistry.Decoder("json"); err == nil {
+		if registered, ok := c.(*json.Codec[T]); ok {
+			cdc = registered.Clone().(*json.Codec[T])
+		}
+	}
+	if !cdc.CheckDecodeOption() {
+		cdc.ApplyDecodeOption(&g.globalDecodeOpt)
+	}
+	if !cdc.CheckEncodeOption() {
+		cdc.ApplyEncodeOption(&g.globalEncodeOpt)
+	}
+	return cdc
+}
+
+// Bind maps values such as a subtree of ToMap, or of a map[string]any config,
+// onto dst, which may point to any type, not just T. Values are converted
+// with the JSON mapping rules and the decode options set for "json", so keys
+// are matched by JSON key and absent keys leave dst untouched. No defaults or
+// validation are applied.
+//
+// Parameters:
+//   - m: The values to bind
+//   - dst: Non-nil pointer to the value to populate
+//
+// Returns an error if dst is not a non-nil pointer or a value does not fit
+// its field.
+//
+// Example:
+//
+//	all, _ := gt.ToMap()
+//	var db DatabaseConfig
+//	err := gt.Bind(all["db"].(map[string]any), &db)
+func (g *Gathuk[T]) Bind(m map[string]any, dst any) error {
+	return g.jsonCodec().Bind(m, dst)
+}
+
+// String renders the current configuration for logging, with secret fields
+// masked. It is the same as RedactedString, so printing a Gathuk with
+// fmt or a logger never leaks secrets.
+func (g *Gathuk[T]) String() string {
+	return g.RedactedString()
+}
+
+// RedactedString renders the current configuration as JSON, with fields
+// tagged with the `secret` option (e.g. `config:"password,secret"`) replaced
+// by "***", including secret fields of nested structs.
+//
+// Returns the rendered configuration, or a description of the error if the
+// configuration cannot be encoded.
+//
+// Example:
+//
+//	log.Printf("effective config: %s", gt.RedactedString())
+//	// {"host": "localhost", "password": "***"}
+func (g *Gathuk[T]) RedactedString() string {
+	cdc := &json.Codec[T]{}
+	cdc.ApplyEncodeOption(&option.EncodeOption{RedactSecrets: true})
+	data, err := cdc.Encode(g.GetConfig())
+	if err != nil {
+		return fmt.Sprintf("<gathuk: %v>", err)
+	}
+	return string(data)
+}
@@ -0,0 +1,342 @@
+// Package gathuk
+package gathuk
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+	"github.com/ahyalfan/gathuk/option"
+)
+
+func TestUnmarshal(t *testing.T) {
+	t.Run("Test 1: env", func(t *testing.T) {
+		var got Simple2
+		err := Unmarshal([]byte("SIMPLE_E=5\nDEBUG_C=true\nDB_USER=admin"), "env", &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, 5, got.Simplee)
+		customtests.Equals(t, true, got.Debug)
+		customtests.Equals(t, "admin", got.Database.User)
+	})
+
+	t.Run("Test 2: json", func(t *testing.T) {
+		var got Simple2
+		err := Unmarshal([]byte(`{"simple_e": 5, "db": {"user": "admin"}}`), "json", &got)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, 5, got.Simplee)
+		customtests.Equals(t, "admin", got.Database.User)
+	})
+
+	t.Run("Test 3: unsupported format", func(t *testing.T) {
+		var got Simple
+		err := Unmarshal([]byte("a: b"), "yaml", &got)
+		customtests.Assert(t, err != nil, "expected error for unsupported format")
+	})
+}
+
+func TestMarshal(t *testing.T) {
+	t.Run("Test 1: env", func(t *testing.T) {
+		got, err := Marshal(Simple{SimpleC: "hore", SimpleE: 1}, "env")
+
+		customtests.OK(t, err)
+		customtests.Assert(t, strings.Contains(string(got), "SIMPLE_C=hore\n"), "missing SIMPLE_C in %q", got)
+		customtests.Assert(t, strings.Contains(string(got), "SIMPLE_E=1\n"), "missing SIMPLE_E in %q", got)
+	})
+
+	t.Run("Test 2: json round trip", func(t *testing.T) {
+		want := Simple2{Simplee: 5, Database: Database{User: "admin"}}
+		data, err := Marshal(want, "json")
+		customtests.OK(t, err)
+
+		var got Simple2
+		err = Unmarshal(data, "json", &got)
+		customtests.OK(t, err)
+		customtests.Equals(t, want, got)
+	})
+
+	t.Run("Test 3: encode options", func(t *testing.T) {
+		type Secret struct {
+			Host     string
+			Password string `config:"password,secret"`
+		}
+
+		got, err := Marshal(Secret{Host: "a", Password: "b"}, "env", &option.EncodeOption{ExcludeSecrets: true})
+		customtests.OK(t, err)
+		customtests.Equals(t, "HOST=a\n", string(got))
+	})
+}
+
+func TestGathukDecodeBytes(t *testing.T) {
+	t.Run("Test 1: decoded value is returned, config untouched", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+		customtests.OK(t, gt.LoadConfig(strings.NewReader(`{"simple_c": "loaded"}`), "json"))
+
+		got, err := gt.DecodeBytes([]byte(`{"simple_c": "decoded", "simple_e": 2}`), "json")
+		customtests.OK(t, err)
+		customtests.Equals(t, Simple{SimpleC: "decoded", SimpleE: 2}, got)
+		customtests.Equals(t, Simple{SimpleC: "loaded"}, gt.GetConfig())
+	})
+
+	t.Run("Test 2: decode options of the instance apply", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+		gt.SetDecodeOption("env", &option.DecodeOption{KeyPrefix: "APP"})
+
+		got, err := gt.DecodeBytes([]byte("APP_SIMPLE_C=prefixed\nSIMPLE_C=plain"), "env")
+		customtests.OK(t, err)
+		customtests.Equals(t, "prefixed", got.SimpleC)
+	})
+
+	t.Run("Test 3: errors", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		_, err := gt.DecodeBytes([]byte("a: b"), "yaml")
+		customtests.Assert(t, err != nil, "expected error for unsupported format")
+
+		_, err = gt.DecodeBytes([]byte(`{"simple_e": "abc"}`), "json")
+		customtests.Assert(t, err != nil, "expected conversion error")
+	})
+
+	t.Run("Test 4: concurrent decodes do not share codec state", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 20)
+		for i := range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				got, err := gt.DecodeBytes(fmt.Appendf(nil, "SIMPLE_C=value-%d\nSIMPLE_E=%d", i, i), "env")
+				if err == nil && (got.SimpleC != fmt.Sprintf("value-%d", i) || got.SimpleE != i) {
+					err = fmt.Errorf("decode %d got %+v", i, got)
+				}
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			customtests.OK(t, err)
+		}
+	})
+}
+
+func TestGathukEncodeBytes(t *testing.T) {
+	t.Run("Test 1: encoded value is returned", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		got, err := gt.EncodeBytes("env", Simple{SimpleC: "hore", SimpleE: 1})
+		customtests.OK(t, err)
+		customtests.Equals(t, "SIMPLE_C=hore\nSIMPLE_E=1\n", string(got))
+		customtests.Equals(t, Simple{}, gt.GetConfig())
+	})
+
+	t.Run("Test 2: round trip with encode options", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+		gt.SetEncodeOption("env", &option.EncodeOption{Header: "generated"})
+
+		data, err := gt.EncodeBytes("env", Simple{SimpleC: "hore"})
+		customtests.OK(t, err)
+		customtests.Assert(t, strings.HasPrefix(string(data), "# generated\n"), "missing header in %q", data)
+
+		got, err := gt.DecodeBytes(data, "env")
+		customtests.OK(t, err)
+		customtests.Equals(t, Simple{SimpleC: "hore"}, got)
+	})
+
+	t.Run("Test 3: unsupported format", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		_, err := gt.EncodeBytes("yaml", Simple{})
+		customtests.Assert(t, err != nil, "expected error for unsupported format")
+	})
+}
+
+func TestGathukToMap(t *testing.T) {
+	type Server struct {
+		Host string `config:"host"`
+		Port int    `config:"port"`
+	}
+	type Config struct {
+		Name    string   `config:"name"`
+		Tags    []string `config:"tags"`
+		Servers []Server `config:"servers"`
+		DB      Database `config:"db"`
+	}
+
+	t.Run("Test 1: nested struct with slices", func(t *testing.T) {
+		gt := NewGathuk[Config]()
+		err := gt.LoadConfig(strings.NewReader(`{
+			"name": "app",
+			"tags": ["a", "b"],
+			"servers": [{"host": "h1", "port": 80}],
+			"db": {"user": "admin", "server_port": "5432", "poling_max_pool": 10}
+		}`), "json")
+		customtests.OK(t, err)
+
+		got, err := gt.ToMap()
+		customtests.OK(t, err)
+		customtests.Equals(t, map[string]any{
+			"name":    "app",
+			"tags":    []any{"a", "b"},
+			"servers": []any{map[string]any{"host": "h1", "port": int64(80)}},
+			"db": map[string]any{
+				"user":            "admin",
+				"server_port":     "5432",
+				"poling_max_pool": int64(10),
+			},
+		}, got)
+	})
+
+	t.Run("Test 2: map is not shared with the configuration", func(t *testing.T) {
+		gt := NewGathuk[Config]()
+		got, err := gt.ToMap()
+		customtests.OK(t, err)
+
+		got["name"] = "changed"
+		customtests.Equals(t, "", gt.GetConfig().Name)
+	})
+
+	t.Run("Test 3: registered codec is left untouched", func(t *testing.T) {
+		gt := NewGathuk[Config]()
+		_, err := gt.ToMap()
+		customtests.OK(t, err)
+
+		enc, err := gt.CodecRegistry.Encoder("json")
+		customtests.OK(t, err)
+		customtests.Assert(t, !enc.CheckEncodeOption(), "ToMap applied options to the registered codec")
+	})
+}
+
+func TestGathukBind(t *testing.T) {
+	t.Run("Test 1: bind a sub-map into a local struct", func(t *testing.T) {
+		gt := NewGathuk[Simple2]()
+		err := gt.LoadConfigString(`{"simple_e": 5, "db": {"user": "admin", "server_port": "5432", "poling_max_pool": 10}}`, "json")
+		customtests.OK(t, err)
+
+		all, err := gt.ToMap()
+		customtests.OK(t, err)
+
+		var db Database
+		err = gt.Bind(all["db"].(map[string]any), &db)
+		customtests.OK(t, err)
+		customtests.Equals(t, Database{User: "admin", Server: "5432", PoolingMax: 10}, db)
+	})
+
+	t.Run("Test 2: hand written map", func(t *testing.T) {
+		type Pool struct {
+			Size    int      `config:"size"`
+			Timeout float64  `config:"timeout"`
+			Hosts   []string `config:"hosts"`
+		}
+		gt := NewGathuk[Simple]()
+
+		got := Pool{Size: 1}
+		err := gt.Bind(map[string]any{"timeout": 1.5, "hosts": []any{"a", "b"}}, &got)
+		customtests.OK(t, err)
+		customtests.Equals(t, Pool{Size: 1, Timeout: 1.5, Hosts: []string{"a", "b"}}, got)
+	})
+
+	t.Run("Test 3: type mismatch and bad destination", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		var db Database
+		err := gt.Bind(map[string]any{"poling_max_pool": "many"}, &db)
+		customtests.Assert(t, err != nil, "expected conversion error")
+
+		err = gt.Bind(map[string]any{}, db)
+		customtests.Assert(t, err != nil, "expected error for a non-pointer destination")
+	})
+
+	t.Run("Test 4: registered codec is left untouched", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+		var db Database
+		customtests.OK(t, gt.Bind(map[string]any{"user": "admin"}, &db))
+
+		dec, err := gt.CodecRegistry.Decoder("json")
+		customtests.OK(t, err)
+		customtests.Assert(t, !dec.CheckDecodeOption(), "Bind applied options to the registered codec")
+	})
+}
+
+func TestGathukBindStruct(t *testing.T) {
+	type Section struct {
+		Database Database `config:"db"`
+	}
+
+	t.Run("Test 1: bind a section of an env file", func(t *testing.T) {
+		gt := NewGathuk[Simple2]()
+		customtests.OK(t, gt.LoadConfigString("SIMPLE_E=5", "env"))
+
+		var got Section
+		err := gt.BindStruct(&got, "env", strings.NewReader("SIMPLE_E=9\nDB_USER=admin\nDB_SERVER_PORT=5432"))
+		customtests.OK(t, err)
+		customtests.Equals(t, Database{User: "admin", Server: "5432"}, got.Database)
+		// the loaded configuration is not touched
+		customtests.Equals(t, 5, gt.GetConfig().Simplee)
+	})
+
+	t.Run("Test 2: bind json into a struct unrelated to T", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		var got Database
+		err := gt.BindStruct(&got, "json", strings.NewReader(`{"user": "admin", "poling_max_pool": 10}`))
+		customtests.OK(t, err)
+		customtests.Equals(t, Database{User: "admin", PoolingMax: 10}, got)
+	})
+
+	t.Run("Test 3: bad destination and format", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		var got Database
+		err := gt.BindStruct(got, "json", strings.NewReader(`{}`))
+		customtests.Assert(t, err != nil, "expected error for a non-pointer destination")
+
+		err = gt.BindStruct(&got, "yaml", strings.NewReader(""))
+		customtests.Assert(t, err != nil, "expected error for an unsupported format")
+	})
+
+	t.Run("Test 4: registered codec is left untouched", func(t *testing.T) {
+		gt := NewGathuk[Simple2]()
+		var got Section
+		customtests.OK(t, gt.BindStruct(&got, "env", strings.NewReader("DB_USER=admin")))
+
+		dec, err := gt.CodecRegistry.Decoder("env")
+		customtests.OK(t, err)
+		customtests.Assert(t, !dec.CheckDecodeOption(), "BindStruct applied options to the registered codec")
+	})
+}
+
+func TestGathukRedactedString(t *testing.T) {
+	type Credentials struct {
+		User     string `config:"user"`
+		Password string `config:"password,secret"`
+	}
+	type Config struct {
+		Host   string      `config:"host"`
+		APIKey string      `config:"api_key,secret"`
+		DB     Credentials `config:"db"`
+	}
+
+	gt := NewGathuk[Config]()
+	err := gt.LoadConfigString("HOST=localhost\nAPI_KEY=key-123\nDB_USER=admin\nDB_PASSWORD=hunter2", "env")
+	customtests.OK(t, err)
+
+	t.Run("Test 1: secrets are masked", func(t *testing.T) {
+		got := gt.RedactedString()
+
+		customtests.Assert(t, strings.Contains(got, `"host": "localhost"`), "missing host in %s", got)
+		customtests.Assert(t, strings.Contains(got, `"user": "admin"`), "missing user in %s", got)
+		customtests.Assert(t, strings.Contains(got, `"api_key": "***"`), "api_key not masked in %s", got)
+		customtests.Assert(t, strings.Contains(got, `"password": "***"`), "password not masked in %s", got)
+		customtests.Assert(t, !strings.Contains(got, "hunter2") && !strings.Contains(got, "key-123"), "secret leaked in %s", got)
+	})
+
+	t.Run("Test 2: String is redacted", func(t *testing.T) {
+		customtests.Equals(t, gt.RedactedString(), gt.String())
+		customtests.Equals(t, "key-123", gt.GetConfig().APIKey)
+	})
+}
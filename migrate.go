@@ -0,0 +1,185 @@
+// Package gathuk
+package gathuk
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ahyalfan/gathuk/internal/encoding/dotenv"
+	"github.com/ahyalfan/gathuk/internal/encoding/json"
+	"github.com/ahyalfan/gathuk/option"
+)
+
+// versionField is the name of the top-level field of T holding the version
+// of the configuration format.
+const versionField = "Version"
+
+// RegisterMigration registers a migration upgrading sources of version from
+// to version from+1.
+//
+// A migration receives the decoded keys of a source and returns the upgraded
+// keys. Keys are the ones of the source format: upper case flat keys with
+// string values such as "DB_HOST" for .env files, nested maps with lower case
+// keys for JSON.
+//
+// Sources are read with the decode options of their format, so with
+// KeyPrefix "APP" the keys of a .env file include the "APP_" prefix.
+//
+// Migrations need T to have a top-level `Version int` field. When a source
+// is loaded, its version key (VERSION in .env, "version" in JSON, or the key
+// given by the field tag) is read, a missing key counting as version 0. The
+// migrations registered for that version and each following one are then
+// applied in turn before the source is mapped to T, so a v1 file runs the
+// v1 and v2 migrations when both exist. The version key is set to the
+// resulting version. Sources without an applicable migration are loaded as is.
+// A migration must return a map; returning nil fails the load.
+//
+// Only the built-in "env" and "json" formats are migrated.
+//
+// Parameters:
+//   - from: The version the migration upgrades from
+//   - fn: The migration
+//
+// Example:
+//
+//	type Config struct {
+//	    Version int
+//	    DBHost  string
+//	}
+//
+//	// v1 files called the key DATABASE_HOST
+//	gt.RegisterMigration(1, func(m map[string]any) map[string]any {
+//	    m["DB_HOST"] = m["DATABASE_HOST"]
+//	    delete(m, "DATABASE_HOST")
+//	    return m
+//	})
+func (g *Gathuk[T]) RegisterMigration(from int, fn func(map[string]any) map[string]any) {
+	if g.migrations == nil {
+		g.migrations = make(map[int]func(map[string]any) map[string]any)
+	}
+	g.migrations[from] = fn
+}
+
+// migrate applies the registered migrations to the content of a source.
+//
+// Parameters:
+//   - by: The content of the source
+//   - format: The format of the source
+//
+// Returns the migrated content, or by itself when nothing was migrated, and
+// an error if the content cannot be decoded or encoded.
+func (g *Gathuk[T]) migrate(by []byte, format string) ([]byte, error) {
+	if len(g.migrations) == 0 {
+		return by, nil
+	}
+	key, ok := g.versionKey(format)
+	if !ok {
+		return by, nil
+	}
+
+	// read the source with the options the real decode uses, so the
+	// migrations see the same keys, but without the OS environment
+	do := *g.decodeOption(format)
+	do.AutomaticEnv = false
+	do.PersistToOSEnv = false
+
+	m := make(map[string]any)
+	switch format {
+	case "env":
+		key = envKeyPrefix(&do) + key
+		// values are kept as strings, as written in the file
+		var raw map[string]string
+		cdc := dotenv.Codec[map[string]string]{}
+		cdc.ApplyDecodeOption(&do)
+		if err := cdc.Decode(by, &raw); err != nil {
+			return nil, err
+		}
+		for k, v := range raw {
+			m[k] = v
+		}
+	case "json":
+		cdc := json.Codec[map[string]any]{}
+		cdc.ApplyDecodeOption(&do)
+		if err := cdc.Decode(by, &m); err != nil {
+			return nil, err
+		}
+	default:
+		return by, nil
+	}
+
+	version, err := parseVersion(m[key])
+	if err != nil {
+		return nil, fmt.Errorf("migrate: %s: %w", key, err)
+	}
+
+	migrated := false
+	for fn, ok := g.migrations[version]; ok; fn, ok = g.migrations[version] {
+		m = fn(m)
+		if m == nil {
+			return nil, fmt.Errorf("migrate: migration from version %d returned nil", version)
+		}
+		version++
+		m[key] = version
+		migrated = true
+	}
+	if !migrated {
+		return by, nil
+	}
+
+	switch format {
+	case "env":
+		cdc := dotenv.Codec[map[string]any]{}
+		return cdc.Encode(m)
+	default:
+		cdc := json.Codec[map[string]any]{}
+		return cdc.Encode(m)
+	}
+}
+
+// versionKey returns the key of the Version field of T in format, and
+// whether T has such a field.
+func (g *Gathuk[T]) versionKey(format string) (string, bool) {
+	for _, f := range typeFields(reflect.TypeOf(&g.value).Elem()) {
+		if f.Path != versionField {
+			continue
+		}
+		if format == "env" {
+			return strings.ToUpper(f.EnvKey), f.EnvKey != ""
+		}
+		return f.JSONKey, f.JSONKey != ""
+	}
+	return "", false
+}
+
+// envKeyPrefix returns the prefix DecodeOption.KeyPrefix adds to the keys of
+// a struct in .env sources, e.g. "APP_" for "app", or "" when none is set.
+func envKeyPrefix(do *option.DecodeOption) string {
+	prefix := strings.TrimSuffix(do.KeyPrefix, "_")
+	if prefix == "" {
+		return ""
+	}
+	return strings.ToUpper(prefix) + "_"
+}
+
+// parseVersion converts a decoded version value to an int. A missing value
+// is version 0.
+func parseVersion(v any) (int, error) {
+	switch v := v.(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v != float64(int(v)) {
+			return 0, fmt.Errorf("version %v is not an integer", v)
+		}
+		return int(v), nil
+	case string:
+		return strconv.Atoi(v)
+	}
+	return 0, fmt.Errorf("unsupported version %v", v)
+}
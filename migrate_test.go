@@ -0,0 +1,97 @@
+// Package gathuk
+package gathuk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+	"github.com/ahyalfan/gathuk/option"
+)
+
+type VersionedConfig struct {
+	Version int    `config:"version"`
+	DBHost  string `config:"db_host"`
+	Port    int    `config:"port"`
+}
+
+// renameDatabaseHost is the v1 to v2 migration: DATABASE_HOST became DB_HOST.
+func renameDatabaseHost(key, newKey string) func(map[string]any) map[string]any {
+	return func(m map[string]any) map[string]any {
+		if v, ok := m[key]; ok {
+			m[newKey] = v
+			delete(m, key)
+		}
+		return m
+	}
+}
+
+func TestGathukMigration(t *testing.T) {
+	t.Run("Test 1: v1 env key is renamed", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.env")
+		customtests.OK(t, os.WriteFile(path, []byte("VERSION=1\nDATABASE_HOST=db.local\nPORT=5432\n"), 0o644))
+
+		gt := NewGathuk[VersionedConfig]()
+		gt.RegisterMigration(1, renameDatabaseHost("DATABASE_HOST", "DB_HOST"))
+		err := gt.LoadConfigFiles(path)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, VersionedConfig{Version: 2, DBHost: "db.local", Port: 5432}, gt.GetConfig())
+	})
+
+	t.Run("Test 2: v1 json key is renamed", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		customtests.OK(t, os.WriteFile(path, []byte(`{"version": 1, "database_host": "db.local", "port": 5432}`), 0o644))
+
+		gt := NewGathuk[VersionedConfig]()
+		gt.RegisterMigration(1, renameDatabaseHost("database_host", "db_host"))
+		err := gt.LoadConfigFiles(path)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, VersionedConfig{Version: 2, DBHost: "db.local", Port: 5432}, gt.GetConfig())
+	})
+
+	t.Run("Test 3: current version is loaded as is", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.env")
+		customtests.OK(t, os.WriteFile(path, []byte("VERSION=2\nDB_HOST=db.local\nDATABASE_HOST=ignored\n"), 0o644))
+
+		gt := NewGathuk[VersionedConfig]()
+		gt.RegisterMigration(1, renameDatabaseHost("DATABASE_HOST", "DB_HOST"))
+		err := gt.LoadConfigFiles(path)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, VersionedConfig{Version: 2, DBHost: "db.local"}, gt.GetConfig())
+	})
+
+	t.Run("Test 4: migrations are chained", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		customtests.OK(t, os.WriteFile(path, []byte(`{"version": 1, "host": "db.local"}`), 0o644))
+
+		gt := NewGathuk[VersionedConfig]()
+		gt.RegisterMigration(1, renameDatabaseHost("host", "database_host"))
+		gt.RegisterMigration(2, renameDatabaseHost("database_host", "db_host"))
+		err := gt.LoadConfigFiles(path)
+
+		customtests.OK(t, err)
+		customtests.Equals(t, VersionedConfig{Version: 3, DBHost: "db.local"}, gt.GetConfig())
+	})
+
+	t.Run("Test 5: env decode options apply", func(t *testing.T) {
+		gt := NewGathuk[VersionedConfig]()
+		gt.SetDecodeOption("env", &option.DecodeOption{KeyPrefix: "APP", CommentPrefixes: []string{";"}})
+		gt.RegisterMigration(1, renameDatabaseHost("APP_DATABASE_HOST", "APP_DB_HOST"))
+		err := gt.LoadConfigString("; VERSION=3\nAPP_VERSION=1\nAPP_DATABASE_HOST=db.local\n", "env")
+
+		customtests.OK(t, err)
+		customtests.Equals(t, VersionedConfig{Version: 2, DBHost: "db.local"}, gt.GetConfig())
+	})
+
+	t.Run("Test 6: migration returning nil", func(t *testing.T) {
+		gt := NewGathuk[VersionedConfig]()
+		gt.RegisterMigration(1, func(map[string]any) map[string]any { return nil })
+		err := gt.LoadConfigString(`{"version": 1}`, "json")
+
+		customtests.Assert(t, err != nil, "expected error for a migration returning nil")
+	})
+}
@@ -1,6 +1,8 @@
 // Package option
 package option
 
+import "time"
+
 // DecodeOption contains options that control how configuration data is decoded
 // from files and environment variables.
 //
@@ -18,6 +20,92 @@ type DecodeOption struct {
 	AutomaticEnv      bool // jika true, baca OS environment otomatis
 	PersistToOSEnv    bool // jika true, hasil decode disimpan di OS env juga
 	PreferFileOverEnv bool // jika true, config file diutamakan dibanding OS env / string
+
+	// CommentPrefixes lists the markers that start a comment in line based
+	// formats such as .env. A line is a comment when its trimmed content starts
+	// with one of the prefixes; an inline comment must be preceded by whitespace.
+	// Defaults to ["#"] when empty.
+	CommentPrefixes []string
+
+	// CaseInsensitiveKeys makes object keys of formats such as JSON match
+	// struct fields regardless of case, so "PORT", "port" and "Port" all map
+	// to the same field. An exact match is preferred when several keys fold
+	// to the same name.
+	CaseInsensitiveKeys bool
+
+	// MaxValueLen rejects input containing a single value longer than this
+	// many bytes, guarding against huge values in untrusted config.
+	// 0 means unlimited.
+	MaxValueLen int
+
+	// MaxKeys rejects input declaring more than this many keys in total
+	// (including keys of nested objects), guarding against memory exhaustion
+	// from hostile config. 0 means unlimited.
+	MaxKeys int
+
+	// TrimSpace controls whether leading and trailing whitespace is trimmed
+	// from values of line based formats such as .env, e.g. the spaces in
+	// "NAME=alice   # comment". A nil pointer means true; point it at false
+	// to keep values byte for byte.
+	TrimSpace *bool
+
+	// SingleValueAsSlice lets a scalar value decode into a slice field as a
+	// one-element slice, for sources that send either "a" or ["a"] for the
+	// same key.
+	SingleValueAsSlice bool
+
+	// StrictTypes makes typed formats such as JSON reject values whose type
+	// does not match the field, e.g. the string "8080" for an int field,
+	// instead of converting them. Useful to catch schema drift.
+	StrictTypes bool
+
+	// StrictJSON makes the JSON codec reject string literals holding raw
+	// control characters (below 0x20) such as tabs or newlines, which the
+	// JSON spec requires to be escaped. By default they are accepted as is.
+	StrictJSON bool
+
+	// StrictEnv makes the .env codec reject values that are not double
+	// quoted but hold whitespace, "#" or "=", e.g. PASSWORD=abc #def, which
+	// would otherwise be read as "abc" with a comment. Comments are still
+	// allowed on their own line or after a quoted value.
+	StrictEnv bool
+
+	// SecretResolver, when set, is called for every value holding a secret
+	// reference such as "secret://vault/db", with the reference after the
+	// "secret://" prefix ("vault/db"). The value is replaced by the returned
+	// plaintext, so secret managers can be plugged in without a dependency.
+	SecretResolver func(ref string) (string, error)
+
+	// KeyPrefix namespaces the keys of line based formats such as .env, so
+	// apps sharing one environment can keep their own variables apart. With
+	// KeyPrefix "MYAPP", a Port field reads MYAPP_PORT and a nested DB.Host
+	// field reads MYAPP_DB_HOST. A trailing "_" is optional. The .env codec
+	// also writes the prefix when encoding, so written files read back.
+	KeyPrefix string
+
+	// ExtendedBool makes line based formats such as .env accept yes/no, y/n
+	// and on/off (in any case) for bool fields, besides the spellings of
+	// strconv.ParseBool such as true/false and 1/0.
+	ExtendedBool bool
+
+	// DefaultLocation is the time zone of time.Time values written without
+	// one, such as "2023-01-02 15:04:05" or "2023-01-02". A `tz` tag on a
+	// field (e.g. `tz:"America/New_York"`) takes precedence. Defaults to UTC.
+	// Values with an explicit offset, such as RFC 3339, keep their own zone.
+	DefaultLocation *time.Location
+
+	// RepeatedKeysAsSlice makes a key listed several times in a .env file,
+	// such as TAG=a and TAG=b, fill a slice field with all of its values in
+	// order, instead of only keeping the last one. Each occurrence may still
+	// be a comma-separated list. Non-slice fields get the last value.
+	RepeatedKeysAsSlice bool
+
+	// NestedSeparator joins the prefix of a nested struct to its keys in
+	// .env files, "_" by default. With "__", Database.Host tagged
+	// `config:"db"` reads DB__HOST, following the Docker and Kubernetes
+	// convention. Encoding with the same codec writes the same keys. The
+	// KeyPrefix is still joined with "_", e.g. MYAPP_DB__HOST.
+	NestedSeparator string
 }
 
 // EncodeOption contains options that control how configuration data is encoded
@@ -35,6 +123,32 @@ type DecodeOption struct {
 type EncodeOption struct {
 	AutomaticEnv      bool // jika true, baca OS environment otomatis
 	PreferFileOverEnv bool // jika true, config file diutamakan dibanding OS env / string
+
+	// ExcludeSecrets leaves fields tagged with the `secret` option
+	// (e.g. `config:"password,secret"`) out of the encoded output, including
+	// secret fields of nested structs. Secret fields are still decoded.
+	ExcludeSecrets bool
+
+	// RedactSecrets writes fields tagged with the `secret` option as "***"
+	// instead of their value, so the output can be logged safely while still
+	// showing which keys are set. ExcludeSecrets takes precedence.
+	RedactSecrets bool
+
+	// Header is written as a comment block at the top of the encoded output
+	// of formats that support comments, e.g. "Generated by myapp - do not edit".
+	// Every line of a multi-line header gets its own comment prefix. JSON has
+	// no comment syntax, so encoding JSON with a header fails.
+	Header string
+
+	// FloatFormat is the strconv.FormatFloat format used for float fields:
+	// 'f' (no exponent), 'e' (always an exponent) or 'g' (exponent for large
+	// or small values). When zero, .env output uses 'f' and JSON uses 'g'.
+	FloatFormat byte
+
+	// FloatPrecision is the number of digits written for float fields, as in
+	// strconv.FormatFloat. Zero means the smallest number of digits that
+	// reads back to the same value.
+	FloatPrecision int
 }
 
 // DecodeOptionApplier is an interface for types that can accept and apply
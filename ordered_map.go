@@ -0,0 +1,16 @@
+// Package gathuk
+package gathuk
+
+import "github.com/ahyalfan/gathuk/shared"
+
+// OrderedMap is a map of configuration keys that preserves source order
+// through decode and encode. Use it as the type parameter to keep the key
+// order of a file when re-encoding it:
+//
+//	gt := gathuk.NewGathuk[gathuk.OrderedMap]()
+//	err := gt.LoadConfigFiles("config.json")
+//	err = gt.WriteConfigFile("copy.json", 0644, gt.GetConfig()) // same key order
+type OrderedMap = shared.OrderedMap
+
+// KeyValue is a single entry of an OrderedMap.
+type KeyValue = shared.KeyValue
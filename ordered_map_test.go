@@ -0,0 +1,43 @@
+// Package gathuk
+package gathuk
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+func TestOrderedMap(t *testing.T) {
+	t.Run("Test 1: env round trip keeps key order", func(t *testing.T) {
+		src := "ZETA=42\nALPHA=two\nMIDDLE=true\n"
+		gt := NewGathuk[OrderedMap]()
+
+		err := gt.LoadConfig(strings.NewReader(src), "env")
+		customtests.OK(t, err)
+		customtests.Equals(t, []string{"ZETA", "ALPHA", "MIDDLE"}, gt.GetConfig().Keys())
+
+		var out bytes.Buffer
+		err = gt.WriteConfig(&out, "env", gt.GetConfig())
+		customtests.OK(t, err)
+		customtests.Equals(t, src, out.String())
+	})
+
+	t.Run("Test 2: json round trip keeps key order", func(t *testing.T) {
+		src := `{"zeta": 1,"alpha": {"y": "a","b": true},"middle": [1,2]}`
+		gt := NewGathuk[OrderedMap]()
+
+		err := gt.LoadConfig(strings.NewReader(src), "json")
+		customtests.OK(t, err)
+		customtests.Equals(t, []string{"zeta", "alpha", "middle"}, gt.GetConfig().Keys())
+
+		alpha, _ := gt.GetConfig().Get("alpha")
+		customtests.Equals(t, []string{"y", "b"}, alpha.(OrderedMap).Keys())
+
+		var out bytes.Buffer
+		err = gt.WriteConfig(&out, "json", gt.GetConfig())
+		customtests.OK(t, err)
+		customtests.Equals(t, src, out.String())
+	})
+}
@@ -0,0 +1,94 @@
+// Package gathuk
+package gathuk
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultConfigName is the base name ReadInConfig looks for when
+// SetConfigName has not been called.
+const defaultConfigName = "config"
+
+// AddConfigPath adds a directory to the list searched by ReadInConfig.
+// Directories are searched in the order they were added.
+//
+// Parameters:
+//   - dir: The directory to search
+//
+// Example:
+//
+//	gt.AddConfigPath("/etc/myapp")
+//	gt.AddConfigPath("$HOME/.myapp")
+//	gt.AddConfigPath(".")
+func (g *Gathuk[T]) AddConfigPath(dir string) {
+	g.configPaths = append(g.configPaths, dir)
+}
+
+// SetConfigName sets the base name, without extension, of the file
+// ReadInConfig looks for. Defaults to "config".
+//
+// Parameters:
+//   - name: The file name without extension
+//
+// Example:
+//
+//	gt.SetConfigName("myapp") // matches myapp.env, myapp.json, ...
+func (g *Gathuk[T]) SetConfigName(name string) {
+	g.configName = name
+}
+
+// ReadInConfig finds the configuration file in the search paths and loads it.
+//
+// Every directory added with AddConfigPath (the working directory when none
+// was added) is searched in order for a file named after SetConfigName with
+// the extension of a supported format, e.g. config.env or config.json. The
+// first file found is loaded like with LoadConfigFiles; the remaining paths
+// are not searched. Environment variables in paths such as $HOME are expanded.
+//
+// Returns an error listing every searched location if no file is found, or
+// any error loading the file.
+//
+// Example:
+//
+//	gt.SetConfigName("myapp")
+//	gt.AddConfigPath("/etc/myapp")
+//	gt.AddConfigPath(".")
+//	if err := gt.ReadInConfig(); err != nil {
+//	    log.Fatal(err)
+//	}
+func (g *Gathuk[T]) ReadInConfig() error {
+	name := g.configName
+	if name == "" {
+		name = defaultConfigName
+	}
+	dirs := g.configPaths
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+
+	var searched []string
+	for _, dir := range dirs {
+		for _, format := range g.formats() {
+			filename := filepath.Join(os.ExpandEnv(dir), name+"."+format)
+			if info, err := os.Stat(filename); err == nil && !info.IsDir() {
+				return g.loadFiles(context.Background(), []string{filename})
+			}
+			searched = append(searched, filename)
+		}
+	}
+	return fmt.Errorf("config file %q not found in [%s]: %w", name, strings.Join(searched, ", "), ErrFileNotFound)
+}
+
+// formats returns the formats ReadInConfig tries, in order. Registries that
+// can list their formats (see DefaultCodecRegistry.Formats) are asked;
+// otherwise only the built-in formats are tried.
+func (g *Gathuk[T]) formats() []string {
+	if r, ok := g.CodecRegistry.(interface{ Formats() []string }); ok {
+		return r.Formats()
+	}
+	return builtinFormats
+}
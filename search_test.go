@@ -0,0 +1,58 @@
+// Package gathuk
+package gathuk
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+func TestReadInConfig(t *testing.T) {
+	t.Run("Test 1: file found in the second path", func(t *testing.T) {
+		first, second := t.TempDir(), t.TempDir()
+		customtests.OK(t, os.WriteFile(filepath.Join(second, "myapp.json"), []byte(`{"simple_c": "found", "simple_e": 7}`), 0o644))
+
+		gt := NewGathuk[Simple]()
+		gt.SetConfigName("myapp")
+		gt.AddConfigPath(first)
+		gt.AddConfigPath(second)
+
+		err := gt.ReadInConfig()
+		customtests.OK(t, err)
+		customtests.Equals(t, Simple{SimpleC: "found", SimpleE: 7}, gt.GetConfig())
+	})
+
+	t.Run("Test 2: first path wins", func(t *testing.T) {
+		first, second := t.TempDir(), t.TempDir()
+		customtests.OK(t, os.WriteFile(filepath.Join(first, "config.env"), []byte("SIMPLE_C=first"), 0o644))
+		customtests.OK(t, os.WriteFile(filepath.Join(second, "config.env"), []byte("SIMPLE_C=second"), 0o644))
+
+		gt := NewGathuk[Simple]()
+		gt.AddConfigPath(first)
+		gt.AddConfigPath(second)
+
+		err := gt.ReadInConfig()
+		customtests.OK(t, err)
+		customtests.Equals(t, "first", gt.GetConfig().SimpleC)
+	})
+
+	t.Run("Test 3: error lists searched locations", func(t *testing.T) {
+		first, second := t.TempDir(), t.TempDir()
+
+		gt := NewGathuk[Simple]()
+		gt.SetConfigName("myapp")
+		gt.AddConfigPath(first)
+		gt.AddConfigPath(second)
+
+		err := gt.ReadInConfig()
+		customtests.Assert(t, errors.Is(err, fs.ErrNotExist), "expected not exist error, got %v", err)
+		for _, searched := range []string{filepath.Join(first, "myapp.env"), filepath.Join(second, "myapp.json")} {
+			customtests.Assert(t, strings.Contains(err.Error(), searched), "error does not list %s: %v", searched, err)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+// Package shared provides utility types and functions for handling custom tags used in structs.
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// Error categories shared by the codecs and the gathuk package. Errors
+// returned by the library wrap one of them where it applies, so callers can
+// test for a category with errors.Is while the message keeps the details.
+var (
+	// ErrFormatNotSupported reports that no codec handles a format.
+	ErrFormatNotSupported = errors.New("format not supported")
+	// ErrTypeConversion reports that a value cannot be converted to the type
+	// of its field.
+	ErrTypeConversion = errors.New("type conversion failed")
+	// ErrRequiredMissing reports that a field tagged `required:"true"` has no
+	// value.
+	ErrRequiredMissing = errors.New("required value missing")
+	// ErrFileNotFound reports that a configuration file does not exist. It
+	// wraps fs.ErrNotExist.
+	ErrFileNotFound = fmt.Errorf("config file not found: %w", fs.ErrNotExist)
+)
+
+// WrapError marks err as belonging to the error category kind without
+// changing its message: errors.Is reports true for both kind and the errors
+// wrapped by err, and errors.As still finds them. A nil err stays nil.
+//
+// Parameters:
+//   - err: The error to mark
+//   - kind: The category, e.g. ErrTypeConversion
+//
+// Example:
+//
+//	_, err := strconv.Atoi("abc")
+//	err = shared.WrapError(err, shared.ErrTypeConversion)
+//	errors.Is(err, shared.ErrTypeConversion) // true
+//	var numErr *strconv.NumError
+//	errors.As(err, &numErr)                  // true
+func WrapError(err, kind error) error {
+	if err == nil {
+		return nil
+	}
+	return &categoryError{err: err, kind: kind}
+}
+
+// categoryError is an error marked with a category by WrapError.
+type categoryError struct {
+	err  error
+	kind error
+}
+
+func (e *categoryError) Error() string {
+	return e.err.Error()
+}
+
+func (e *categoryError) Unwrap() []error {
+	return []error{e.err, e.kind}
+}
@@ -0,0 +1,29 @@
+// Package shared provides utility types and functions for handling custom tags used in structs.
+package shared
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+func TestWrapError(t *testing.T) {
+	t.Run("Test 1: category and cause are both matched", func(t *testing.T) {
+		_, cause := strconv.Atoi("abc")
+		err := WrapError(cause, ErrTypeConversion)
+
+		customtests.Equals(t, cause.Error(), err.Error())
+		customtests.Assert(t, errors.Is(err, ErrTypeConversion), "expected ErrTypeConversion, got %v", err)
+		customtests.Assert(t, errors.Is(err, strconv.ErrSyntax), "expected strconv.ErrSyntax, got %v", err)
+
+		var numErr *strconv.NumError
+		customtests.Assert(t, errors.As(err, &numErr), "expected *strconv.NumError, got %T", err)
+		customtests.Assert(t, !errors.Is(err, ErrFormatNotSupported), "unexpected ErrFormatNotSupported")
+	})
+
+	t.Run("Test 2: nil stays nil", func(t *testing.T) {
+		customtests.OK(t, WrapError(nil, ErrTypeConversion))
+	})
+}
@@ -0,0 +1,99 @@
+// Package shared provides utility types and functions for handling custom tags used in structs.
+package shared
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Gz64Prefix marks a config value holding gzip compressed data encoded as
+// standard base64, e.g. "gz64:H4sIAAAAAAAA/...". Such values decode into
+// []byte fields. Padding is optional and left out when encoding, since "="
+// is the key-value separator of formats such as .env.
+const Gz64Prefix = "gz64:"
+
+// MaxGz64Len is the default limit on the decompressed size of a Gz64Prefix
+// value, guarding against small values that expand into huge ones.
+const MaxGz64Len = 16 << 20
+
+// IsGz64 reports whether a struct field is a []byte field marked with the
+// `gz64` option, e.g. `config:"logo,gz64"`. Such fields are written as
+// Gz64Prefix values when encoding.
+func IsGz64(field reflect.StructField) bool {
+	return IsBytes(field.Type) && GetTagOptions(field).Contains("gz64")
+}
+
+// IsBytes reports whether t is a byte slice type such as []byte.
+func IsBytes(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+// DecodeGz64 decodes a value carrying the Gz64Prefix.
+//
+// Parameters:
+//   - s: The config value
+//   - limit: The maximum decompressed size in bytes; 0 or less means
+//     MaxGz64Len
+//
+// Returns:
+//   - []byte: The decompressed data
+//   - bool: false if s does not start with Gz64Prefix
+//   - error: An error if the base64 or gzip data is invalid, or the data
+//     decompresses to more than limit bytes
+//
+// Example:
+//
+//	data, ok, err := shared.DecodeGz64(shared.EncodeGz64([]byte("hello")), 0)
+//	// data: []byte("hello"), ok: true, err: nil
+func DecodeGz64(s string, limit int) ([]byte, bool, error) {
+	encoded, ok := strings.CutPrefix(s, Gz64Prefix)
+	if !ok {
+		return nil, false, nil
+	}
+
+	compressed, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(encoded, "="))
+	if err != nil {
+		return nil, true, fmt.Errorf("decode gz64 base64: %w", err)
+	}
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, true, fmt.Errorf("decode gz64 gzip: %w", err)
+	}
+	defer r.Close()
+
+	if limit <= 0 {
+		limit = MaxGz64Len
+	}
+	data, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+	if err != nil {
+		return nil, true, fmt.Errorf("decode gz64 gzip: %w", err)
+	}
+	if len(data) > limit {
+		return nil, true, fmt.Errorf("decode gz64: data exceeds maximum length of %d bytes", limit)
+	}
+	return data, true, nil
+}
+
+// EncodeGz64 compresses data with gzip and returns it as a Gz64Prefix value.
+// Empty data is returned as "".
+//
+// Example:
+//
+//	s := shared.EncodeGz64([]byte("hello")) // "gz64:H4sI..."
+func EncodeGz64(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	// writes to a bytes.Buffer cannot fail
+	_, _ = w.Write(data)
+	_ = w.Close()
+	return Gz64Prefix + base64.RawStdEncoding.EncodeToString(buf.Bytes())
+}
@@ -0,0 +1,59 @@
+// Package shared provides utility types and functions for handling custom tags used in structs.
+package shared
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+func TestGz64(t *testing.T) {
+	t.Run("Test 1: round trip", func(t *testing.T) {
+		blob := bytes.Repeat([]byte("gathuk "), 100)
+		s := EncodeGz64(blob)
+		customtests.Assert(t, strings.HasPrefix(s, Gz64Prefix), "missing prefix in %q", s)
+		customtests.Assert(t, !strings.Contains(s, "="), "unexpected padding in %q", s)
+		customtests.Assert(t, len(s) < len(blob), "expected compressed value, got %d bytes", len(s))
+
+		got, ok, err := DecodeGz64(s, 0)
+		customtests.OK(t, err)
+		customtests.Assert(t, ok, "expected gz64 value")
+		customtests.Equals(t, blob, got)
+	})
+
+	t.Run("Test 2: padded value", func(t *testing.T) {
+		// gzip of "hello" in padded standard base64
+		got, ok, err := DecodeGz64(Gz64Prefix+"H4sIAAAAAAAA/8pIzcnJBwQAAP//hqYQNgUAAAA=", 0)
+		customtests.OK(t, err)
+		customtests.Assert(t, ok, "expected gz64 value")
+		customtests.Equals(t, []byte("hello"), got)
+	})
+
+	t.Run("Test 3: value without prefix", func(t *testing.T) {
+		_, ok, err := DecodeGz64("plain", 0)
+		customtests.OK(t, err)
+		customtests.Assert(t, !ok, "unexpected gz64 value")
+	})
+
+	t.Run("Test 4: invalid data", func(t *testing.T) {
+		_, _, err := DecodeGz64(Gz64Prefix+"not base64!", 0)
+		customtests.Assert(t, err != nil, "expected base64 error")
+
+		_, _, err = DecodeGz64(Gz64Prefix+"aGVsbG8=", 0)
+		customtests.Assert(t, err != nil, "expected gzip error")
+	})
+
+	t.Run("Test 5: decompressed size limit", func(t *testing.T) {
+		blob := bytes.Repeat([]byte("a"), 1000)
+		s := EncodeGz64(blob)
+
+		got, _, err := DecodeGz64(s, 1000)
+		customtests.OK(t, err)
+		customtests.Equals(t, blob, got)
+
+		_, _, err = DecodeGz64(s, 999)
+		customtests.Assert(t, err != nil, "expected size limit error")
+	})
+}
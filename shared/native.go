@@ -0,0 +1,71 @@
+// Package shared provides utility types and functions for handling custom tags used in structs.
+package shared
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// InferScalar converts an untyped config value, such as a .env value, to the
+// Go type a typed format like JSON would give the same literal when it is
+// stored in an `any` field:
+//   - "true" and "false" (in any case) become bool
+//   - Integers in base 10 that fit int64 become int64
+//   - Larger positive integers that fit uint64 become uint64, so values such
+//     as 18446744073709551615 keep every digit instead of losing precision
+//     as a float64
+//   - Other finite numbers become float64
+//   - Everything else stays a string, including "t", "yes", "NaN" and "Inf"
+//
+// Sharing this rule keeps the dynamic type of an `any` field the same
+// whichever format a configuration is loaded from.
+//
+// Example:
+//
+//	shared.InferScalar("8080")  // int64(8080)
+//	shared.InferScalar("0.5")   // float64(0.5)
+//	shared.InferScalar("TRUE")  // true
+//	shared.InferScalar("1")     // int64(1)
+//	shared.InferScalar("18446744073709551615") // uint64(18446744073709551615)
+//	shared.InferScalar("hello") // "hello"
+func InferScalar(s string) any {
+	switch strings.ToLower(s) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return u
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		return f
+	}
+	return s
+}
+
+// FormatFloat formats f like strconv.FormatFloat, for encoders honouring the
+// FloatFormat and FloatPrecision encode options.
+//
+// A zero format falls back to def, the format the encoder uses by default.
+// A precision of zero or less gives the smallest number of digits that reads
+// back to exactly f at the given bit size.
+//
+// Example:
+//
+//	shared.FormatFloat(1e-7, 0, 'f', 0, 64)      // "0.0000001"
+//	shared.FormatFloat(1e-7, 'e', 'f', 2, 64)    // "1.00e-07"
+//	shared.FormatFloat(3.14159, 'f', 'g', 2, 64) // "3.14"
+func FormatFloat(f float64, format, def byte, prec, bitSize int) string {
+	if format == 0 {
+		format = def
+	}
+	if prec <= 0 {
+		prec = -1
+	}
+	return strconv.FormatFloat(f, format, prec, bitSize)
+}
@@ -0,0 +1,49 @@
+// Package shared provides utility types and functions for handling custom tags used in structs.
+package shared
+
+import (
+	"math"
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+func TestInferScalar(t *testing.T) {
+	t.Run("Test 1: typed literals", func(t *testing.T) {
+		customtests.Equals(t, any(int64(8080)), InferScalar("8080"))
+		customtests.Equals(t, any(int64(-1)), InferScalar("-1"))
+		customtests.Equals(t, any(0.5), InferScalar("0.5"))
+		customtests.Equals(t, any(true), InferScalar("TRUE"))
+		customtests.Equals(t, any(false), InferScalar("false"))
+	})
+
+	t.Run("Test 3: integers beyond int64", func(t *testing.T) {
+		customtests.Equals(t, any(uint64(math.MaxUint64)), InferScalar("18446744073709551615"))
+		customtests.Equals(t, any(uint64(math.MaxInt64)+1), InferScalar("9223372036854775808"))
+		customtests.Equals(t, any(int64(math.MinInt64)), InferScalar("-9223372036854775808"))
+		// too large for uint64 as well
+		customtests.Equals(t, any(1.8446744073709552e+19), InferScalar("18446744073709551616"))
+	})
+
+	t.Run("Test 2: ambiguous values", func(t *testing.T) {
+		customtests.Equals(t, any(int64(1)), InferScalar("1"))
+		customtests.Equals(t, any("t"), InferScalar("t"))
+		customtests.Equals(t, any("NaN"), InferScalar("NaN"))
+		customtests.Equals(t, any("Inf"), InferScalar("Inf"))
+		customtests.Equals(t, any("0x10"), InferScalar("0x10"))
+		customtests.Equals(t, any(""), InferScalar(""))
+	})
+}
+
+func TestFormatFloat(t *testing.T) {
+	t.Run("Test 1: defaults", func(t *testing.T) {
+		customtests.Equals(t, "0.0000001", FormatFloat(1e-7, 0, 'f', 0, 64))
+		customtests.Equals(t, "1e-07", FormatFloat(1e-7, 0, 'g', 0, 64))
+		customtests.Equals(t, "0.1", FormatFloat(float64(float32(0.1)), 0, 'f', 0, 32))
+	})
+
+	t.Run("Test 2: format and precision", func(t *testing.T) {
+		customtests.Equals(t, "1.00e-07", FormatFloat(1e-7, 'e', 'f', 2, 64))
+		customtests.Equals(t, "3.14", FormatFloat(3.14159, 'f', 'g', 2, 64))
+	})
+}
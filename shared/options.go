@@ -0,0 +1,158 @@
+// Package shared provides utility types and functions for handling custom tags used in structs.
+package shared
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// TagOptions is the comma-separated list of options that may follow the name
+// in a struct tag, e.g. "secret" in `config:"api_key,secret"`.
+type TagOptions string
+
+// ParseTag splits a struct tag value into its name and its options.
+//
+// Example:
+//
+//	name, opts := shared.ParseTag("api_key,secret")
+//	// name: "api_key", opts: "secret"
+func ParseTag(tag string) (string, TagOptions) {
+	name, opts, _ := strings.Cut(tag, ",")
+	return name, TagOptions(opts)
+}
+
+// Contains reports whether the option list includes the given option.
+//
+// Example:
+//
+//	_, opts := shared.ParseTag("api_key,secret,inline")
+//	opts.Contains("secret") // Returns: true
+//	opts.Contains("omit")   // Returns: false
+func (o TagOptions) Contains(option string) bool {
+	for o != "" {
+		current, rest, _ := strings.Cut(string(o), ",")
+		if current == option {
+			return true
+		}
+		o = TagOptions(rest)
+	}
+	return false
+}
+
+// GetTagOptions returns the options declared in the `config` tag of a struct
+// field (see GetTagName).
+//
+// Example:
+//
+//	type Config struct {
+//	    APIKey string `config:"api_key,secret"`
+//	}
+//	// GetTagOptions(field).Contains("secret") == true
+func GetTagOptions(field reflect.StructField) TagOptions {
+	_, opts := ParseTag(field.Tag.Get(string(GetTagName())))
+	return opts
+}
+
+// Redacted is the placeholder written instead of the value of a secret field
+// when encoding with option.EncodeOption.RedactSecrets.
+const Redacted = "***"
+
+// IsSecret reports whether a struct field is marked with the `secret` option,
+// e.g. `config:"password,secret"`. Secret fields are decoded as usual but can be
+// left out or masked when encoding (see option.EncodeOption.ExcludeSecrets
+// and RedactSecrets).
+func IsSecret(field reflect.StructField) bool {
+	return GetTagOptions(field).Contains("secret")
+}
+
+// IsInline reports whether an exported struct field is marked with the
+// `inline` option, e.g. `config:",inline"`, so its fields are read and written
+// as if declared on the parent struct, without a prefix.
+func IsInline(field reflect.StructField) bool {
+	return field.IsExported() && field.Type.Kind() == reflect.Struct && GetTagOptions(field).Contains("inline")
+}
+
+// IsPromoted reports whether the fields of an embedded (anonymous) struct field
+// are promoted to the level of the parent struct, as encoding/json does.
+//
+// A field is promoted when it embeds a struct type and none of the given tags
+// gives it an explicit name; a named embedded struct is treated as a regular
+// nested struct instead. An exported struct field with the `inline` option,
+// e.g. `config:",inline"`, is always promoted (see IsInline).
+//
+// Example:
+//
+//	type Config struct {
+//	    CommonConfig                     // promoted: HOST instead of COMMON_CONFIG_HOST
+//	    Auth AuthConfig `config:"auth"`  // regular nested field
+//	    Base BaseConfig                  // regular nested field (not embedded)
+//	    Log  LogConfig `config:",inline"` // promoted: LEVEL instead of LOG_LEVEL
+//	}
+func IsPromoted(field reflect.StructField, tags ...string) bool {
+	if IsInline(field) {
+		return true
+	}
+	if !field.Anonymous || field.Type.Kind() != reflect.Struct {
+		return false
+	}
+	for _, tag := range tags {
+		if name, _ := ParseTag(field.Tag.Get(tag)); name != "" {
+			return false
+		}
+	}
+	return field.Tag.Get(string(GetTagName())) != "-"
+}
+
+// IsCatchAll reports whether a struct field is a catch-all map marked with the
+// `catchall` option, e.g. `config:",catchall"`. A catch-all field receives the
+// keys of a file that do not map to any declared field of its struct.
+func IsCatchAll(field reflect.StructField) bool {
+	return field.Type.Kind() == reflect.Map && GetTagOptions(field).Contains("catchall")
+}
+
+// FieldOrder returns the field indices of struct type t in the order encoders
+// write them.
+//
+// Fields with an `order` tag come first, in ascending order of its value;
+// ties keep their declaration order. Fields without a valid `order` tag follow
+// in declaration order.
+//
+// Example:
+//
+//	type Config struct {
+//	    Debug bool
+//	    Name  string `order:"1"`
+//	    Port  int    `order:"2"`
+//	}
+//	// FieldOrder: [1 2 0] (Name, Port, Debug)
+func FieldOrder(t reflect.Type) []int {
+	indices := make([]int, t.NumField())
+	for i := range indices {
+		indices[i] = i
+	}
+
+	slices.SortStableFunc(indices, func(a, b int) int {
+		orderA, okA := fieldOrder(t.Field(a))
+		orderB, okB := fieldOrder(t.Field(b))
+		switch {
+		case okA && okB:
+			return cmp.Compare(orderA, orderB)
+		case okA:
+			return -1
+		case okB:
+			return 1
+		}
+		return 0
+	})
+	return indices
+}
+
+// fieldOrder returns the value of the `order` tag of a field, and whether it
+// has a valid one.
+func fieldOrder(field reflect.StructField) (int, bool) {
+	order, err := strconv.Atoi(field.Tag.Get("order"))
+	return order, err == nil
+}
@@ -0,0 +1,42 @@
+// Package shared provides utility types and functions for handling custom tags used in structs.
+package shared
+
+import (
+	"reflect"
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+func TestParseTag(t *testing.T) {
+	t.Run("Test 1: name and options", func(t *testing.T) {
+		name, opts := ParseTag("api_key,secret,inline")
+		customtests.Equals(t, "api_key", name)
+		customtests.Equals(t, true, opts.Contains("secret"))
+		customtests.Equals(t, true, opts.Contains("inline"))
+		customtests.Equals(t, false, opts.Contains("api_key"))
+	})
+
+	t.Run("Test 2: options without name", func(t *testing.T) {
+		name, opts := ParseTag(",secret")
+		customtests.Equals(t, "", name)
+		customtests.Equals(t, true, opts.Contains("secret"))
+
+		name, opts = ParseTag("port")
+		customtests.Equals(t, "port", name)
+		customtests.Equals(t, false, opts.Contains(""))
+	})
+}
+
+func TestFieldOrder(t *testing.T) {
+	t.Run("Test 1: tagged fields first, ties in declaration order", func(t *testing.T) {
+		type Config struct {
+			Debug bool
+			Port  int    `order:"2"`
+			Name  string `order:"1"`
+			Host  string `order:"2"`
+			Bad   string `order:"x"`
+		}
+		customtests.Equals(t, []int{2, 1, 3, 0, 4}, FieldOrder(reflect.TypeOf(Config{})))
+	})
+}
@@ -0,0 +1,60 @@
+// Package shared provides utility types and functions for handling custom tags used in structs.
+package shared
+
+import "reflect"
+
+// KeyValue is a single entry of an OrderedMap.
+type KeyValue struct {
+	Key   string
+	Value any
+}
+
+// OrderedMap is a map of configuration keys that remembers the order in which
+// keys were inserted.
+//
+// Codecs decode into an OrderedMap in source document order and encode it back
+// in the same order, so re-encoding a decoded config gives stable output.
+// Nested JSON objects are decoded as OrderedMap values as well.
+//
+// Example:
+//
+//	var m OrderedMap
+//	m.Set("port", 8080)
+//	m.Set("host", "localhost")
+//	m.Keys() // Returns: ["port", "host"]
+type OrderedMap []KeyValue
+
+// OrderedMapType is the reflect.Type of OrderedMap, used by codecs to detect
+// an ordered map target.
+var OrderedMapType = reflect.TypeOf(OrderedMap{})
+
+// Get returns the value stored under key, and whether the key exists.
+func (m OrderedMap) Get(key string) (any, bool) {
+	for _, kv := range m {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Set stores value under key. An existing key keeps its position; a new key
+// is appended at the end.
+func (m *OrderedMap) Set(key string, value any) {
+	for i, kv := range *m {
+		if kv.Key == key {
+			(*m)[i].Value = value
+			return
+		}
+	}
+	*m = append(*m, KeyValue{Key: key, Value: value})
+}
+
+// Keys returns the keys in insertion order.
+func (m OrderedMap) Keys() []string {
+	keys := make([]string, len(m))
+	for i, kv := range m {
+		keys[i] = kv.Key
+	}
+	return keys
+}
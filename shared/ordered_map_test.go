@@ -0,0 +1,26 @@
+// Package shared provides utility types and functions for handling custom tags used in structs.
+package shared
+
+import (
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+func TestOrderedMap(t *testing.T) {
+	t.Run("Test 1: insertion order", func(t *testing.T) {
+		var m OrderedMap
+		m.Set("port", 8080)
+		m.Set("host", "localhost")
+		m.Set("port", 9090)
+
+		customtests.Equals(t, []string{"port", "host"}, m.Keys())
+
+		v, ok := m.Get("port")
+		customtests.Equals(t, true, ok)
+		customtests.Equals(t, 9090, v)
+
+		_, ok = m.Get("missing")
+		customtests.Equals(t, false, ok)
+	})
+}
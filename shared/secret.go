@@ -0,0 +1,41 @@
+// Package shared provides utility types and functions for handling custom tags used in structs.
+package shared
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SecretRefPrefix marks a config value referencing a secret held elsewhere,
+// e.g. "secret://vault/db". Such values are passed to a secret resolver.
+const SecretRefPrefix = "secret://"
+
+// ResolveSecret replaces a secret reference with the value returned by
+// resolver. Values without SecretRefPrefix, or any value when resolver is
+// nil, are returned unchanged.
+//
+// Parameters:
+//   - value: The config value
+//   - resolver: Function looking up a reference, called without the prefix
+//
+// Returns:
+//   - string: The resolved value
+//   - error: An error if the resolver fails
+//
+// Example:
+//
+//	v, err := shared.ResolveSecret("secret://vault/db", func(ref string) (string, error) {
+//	    return vault.Read(ref) // ref: "vault/db"
+//	})
+func ResolveSecret(value string, resolver func(ref string) (string, error)) (string, error) {
+	ref, ok := strings.CutPrefix(value, SecretRefPrefix)
+	if !ok || resolver == nil {
+		return value, nil
+	}
+
+	resolved, err := resolver(ref)
+	if err != nil {
+		return "", fmt.Errorf("resolve secret %q: %w", ref, err)
+	}
+	return resolved, nil
+}
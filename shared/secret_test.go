@@ -0,0 +1,39 @@
+// Package shared provides utility types and functions for handling custom tags used in structs.
+package shared
+
+import (
+	"errors"
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+func TestResolveSecret(t *testing.T) {
+	resolver := func(ref string) (string, error) {
+		if ref == "vault/db" {
+			return "s3cr3t", nil
+		}
+		return "", errors.New("not found")
+	}
+
+	t.Run("Test 1: reference is resolved", func(t *testing.T) {
+		got, err := ResolveSecret("secret://vault/db", resolver)
+		customtests.OK(t, err)
+		customtests.Equals(t, "s3cr3t", got)
+	})
+
+	t.Run("Test 2: plain value and nil resolver", func(t *testing.T) {
+		got, err := ResolveSecret("localhost", resolver)
+		customtests.OK(t, err)
+		customtests.Equals(t, "localhost", got)
+
+		got, err = ResolveSecret("secret://vault/db", nil)
+		customtests.OK(t, err)
+		customtests.Equals(t, "secret://vault/db", got)
+	})
+
+	t.Run("Test 3: resolver error", func(t *testing.T) {
+		_, err := ResolveSecret("secret://vault/other", resolver)
+		customtests.Assert(t, err != nil, "expected resolver error")
+	})
+}
@@ -1,6 +1,12 @@
 // Package shared provides utility types and functions for handling custom tags used in structs.
 package shared
 
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
 // Tag is a custom type alias for string that is used to represent tags in struct field annotations.
 // This allows for better type safety and clearer intent when working with struct tags, especially in
 // scenarios where specific tags like "config" or "nested" are used to define struct field properties
@@ -13,3 +19,136 @@ func (t *Tag) Set(v Tag) {
 func (t Tag) Get() Tag {
 	return t
 }
+
+// timeType is the reflect.Type of time.Time.
+var timeType = reflect.TypeOf(time.Time{})
+
+// zonelessLayouts are the time layouts accepted besides RFC 3339, for
+// timestamps written without a time zone.
+var zonelessLayouts = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// ParseTime parses a config timestamp. RFC 3339 values keep their own offset;
+// values without a zone, such as "2023-01-02 15:04:05", "2023-01-02T15:04:05"
+// or "2023-01-02", are interpreted in loc, or in UTC when loc is nil.
+//
+// Example:
+//
+//	ny, _ := time.LoadLocation("America/New_York")
+//	t, err := shared.ParseTime("2023-01-02 15:04:05", ny) // 15:04:05 EST
+func ParseTime(val string, loc *time.Location) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, val)
+	if err == nil {
+		return t, nil
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	for _, layout := range zonelessLayouts {
+		if t, zerr := time.ParseInLocation(layout, val, loc); zerr == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// IsScalarStruct reports whether a struct type holds a single value, such as
+// time.Time, and is therefore decoded from one config value instead of being
+// treated as a nested struct with its own keys.
+func IsScalarStruct(t reflect.Type) bool {
+	return t == timeType
+}
+
+// IsMapKeyType reports whether maps keyed by t can be decoded from config
+// keys, which are always text: strings, and integers, unsigned integers,
+// floats and booleans parsed from the key text, e.g. "8080" for map[int]string.
+func IsMapKeyType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// Defaulter is implemented by config field types that provide their own
+// default value, so reusable types carry sensible defaults wherever they are
+// used. GathukDefault is called on the zero value of the type and must return
+// a value assignable or convertible to it.
+//
+// Example:
+//
+//	type LogLevel string
+//
+//	func (LogLevel) GathukDefault() any { return LogLevel("info") }
+type Defaulter interface {
+	GathukDefault() any
+}
+
+// defaulterType is the reflect.Type of the Defaulter interface.
+var defaulterType = reflect.TypeOf((*Defaulter)(nil)).Elem()
+
+// HasTypeDefault reports whether t, or a pointer to t, implements Defaulter.
+func HasTypeDefault(t reflect.Type) bool {
+	return t.Implements(defaulterType) || reflect.PointerTo(t).Implements(defaulterType)
+}
+
+// ApplyTypeDefault seeds a field whose key is absent from the source with the
+// GathukDefault of its type. Fields that are not settable, already hold a
+// non-zero value (e.g. from an earlier file) or whose type does not implement
+// Defaulter are left untouched.
+//
+// A nil pointer field, such as *LogLevel, gets a newly allocated element set
+// to the default, unless GathukDefault returns a value of the pointer type
+// itself.
+//
+// Returns an error if the default does not fit the field type.
+func ApplyTypeDefault(field reflect.Value) error {
+	if !field.CanSet() || !field.IsZero() || !HasTypeDefault(field.Type()) {
+		return nil
+	}
+
+	t := field.Type()
+	base := t
+	if t.Kind() == reflect.Ptr {
+		base = t.Elem()
+	}
+	// a pointer to base has the methods of both value and pointer receivers
+	def, ok := reflect.New(base).Interface().(Defaulter)
+	if !ok {
+		return nil
+	}
+	d := def.GathukDefault()
+	v := reflect.ValueOf(d)
+	if !v.IsValid() {
+		return nil
+	}
+
+	if t.Kind() == reflect.Ptr && !v.Type().AssignableTo(t) {
+		elem := reflect.New(base)
+		if err := setDefault(elem.Elem(), v, d); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+	return setDefault(field, v, d)
+}
+
+// setDefault assigns or converts the default v, returned as d, to field.
+func setDefault(field, v reflect.Value, d any) error {
+	switch {
+	case v.Type().AssignableTo(field.Type()):
+		field.Set(v)
+	case v.Type().ConvertibleTo(field.Type()):
+		field.Set(v.Convert(field.Type()))
+	default:
+		return fmt.Errorf("default %v (%T) does not fit %s", d, d, field.Type())
+	}
+	return nil
+}
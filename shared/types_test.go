@@ -3,6 +3,7 @@ package shared
 
 import (
 	"testing"
+	"time"
 
 	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
 )
@@ -14,3 +15,30 @@ func TestTypes(t *testing.T) {
 		customtests.Equals(t, Tag("change"), example.Get())
 	})
 }
+
+func TestParseTime(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	customtests.OK(t, err)
+
+	t.Run("Test 1: zoneless in location", func(t *testing.T) {
+		got, err := ParseTime("2023-01-02 15:04:05", ny)
+		customtests.OK(t, err)
+		customtests.Equals(t, time.Date(2023, 1, 2, 15, 4, 5, 0, ny), got)
+
+		got, err = ParseTime("2023-01-02T15:04:05.5", nil)
+		customtests.OK(t, err)
+		customtests.Equals(t, time.Date(2023, 1, 2, 15, 4, 5, 5e8, time.UTC), got)
+	})
+
+	t.Run("Test 2: explicit offset wins", func(t *testing.T) {
+		got, err := ParseTime("2023-01-02T15:04:05+07:00", ny)
+		customtests.OK(t, err)
+		_, offset := got.Zone()
+		customtests.Equals(t, 7*3600, offset)
+	})
+
+	t.Run("Test 3: invalid", func(t *testing.T) {
+		_, err := ParseTime("yesterday", ny)
+		customtests.Assert(t, err != nil, "expected parse error")
+	})
+}
@@ -0,0 +1,225 @@
+// Package gathuk
+package gathuk
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Snapshot returns a deep copy of the current configuration, to be handed
+// back to Restore later. Unlike the value returned by GetConfig, it shares no
+// slices, maps or pointers with the configuration, so later changes made with
+// Set or by loading cannot leak into it.
+//
+// Example:
+//
+//	snap := gt.Snapshot()
+//	_ = gt.Set("Features.NewCheckout", true) // temporary override
+//	// ... run the experiment ...
+//	gt.Restore(snap)
+func (g *Gathuk[T]) Snapshot() T {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return deepCopy(g.value)
+}
+
+// Restore replaces the current configuration with a snapshot taken by
+// Snapshot. The snapshot is copied, so it can be restored more than once.
+//
+// Parameters:
+//   - snap: The configuration to restore
+func (g *Gathuk[T]) Restore(snap T) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.value = deepCopy(snap)
+}
+
+// Set overrides a single field of the current configuration, addressed by its
+// Go field path as listed by Describe, e.g. "Port" or "Database.Host".
+//
+// The value must be assignable to the field type, or a number convertible to
+// a numeric field type without loss (e.g. 8080 for a uint16 field, but not
+// 70000, -1 or 1.5). Pointers to nested structs on the path are allocated
+// when nil. Set does not run validation.
+//
+// Parameters:
+//   - path: The Go field path of a leaf field
+//   - value: The new value
+//
+// Returns an error if no field has the path, or the value does not fit the
+// field type (wrapping ErrTypeConversion).
+//
+// Example:
+//
+//	err := gt.Set("Database.Port", 6543)
+func (g *Gathuk[T]) Set(path string, value any) error {
+	var info *fieldInfo
+	for _, f := range typeFields(reflect.TypeOf(&g.value).Elem()) {
+		if f.Path == path {
+			info = &f
+			break
+		}
+	}
+	if info == nil {
+		return fmt.Errorf("set %s: no such field", path)
+	}
+
+	typ := info.Field.Type
+	v := reflect.ValueOf(value)
+	switch {
+	case !v.IsValid():
+		v = reflect.Zero(typ)
+	case v.Type().AssignableTo(typ):
+	case isNumber(v.Kind()) && isNumber(typ.Kind()):
+		converted, ok := convertNumber(v, typ)
+		if !ok {
+			return fmt.Errorf("set %s: %v overflows %s: %w", path, value, typ, ErrTypeConversion)
+		}
+		v = converted
+	default:
+		return fmt.Errorf("set %s: cannot use %T as %s: %w", path, value, typ, ErrTypeConversion)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	fieldByIndexAlloc(reflect.ValueOf(&g.value).Elem(), info.Index).Set(v)
+	return nil
+}
+
+// fieldByIndexAlloc returns the nested field of v at index, allocating the
+// nil pointers to structs along the way so the field can be set.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v
+}
+
+// Has reports whether a field of the current configuration is set, i.e. holds
+// a non-zero value. The path can be given as the Go field path
+// ("Database.Host") or the dotted config path ("db.host"). A path naming a
+// nested struct ("db") is set when any of its fields is.
+//
+// Parameters:
+//   - path: The field to check
+//
+// Returns false if the field is zero, a pointer on its path is nil or no
+// field has the path.
+//
+// Example:
+//
+//	if gt.Has("tls.cert") {
+//	    // serve HTTPS
+//	}
+func (g *Gathuk[T]) Has(path string) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	root := reflect.ValueOf(&g.value).Elem()
+	for _, f := range typeFields(root.Type()) {
+		if f.Path != path && f.JSONKey != path &&
+			!strings.HasPrefix(f.Path, path+".") && !strings.HasPrefix(f.JSONKey, path+".") {
+			continue
+		}
+		field, err := root.FieldByIndexErr(f.Index)
+		if err == nil && !field.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// isNumber reports whether k is an integer or floating-point kind.
+func isNumber(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// convertNumber converts the number v to the numeric type t, reporting false
+// if the value does not survive the conversion: it overflows t, changes sign
+// or loses a fraction.
+func convertNumber(v reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	out := v.Convert(t)
+	switch {
+	case v.CanInt() && v.Int() < 0 && out.CanUint(),
+		v.CanFloat() && v.Float() < 0 && out.CanUint(),
+		v.CanUint() && out.CanInt() && out.Int() < 0:
+		return out, false
+	case v.CanFloat() && out.CanFloat() && math.IsNaN(v.Float()):
+		return out, true
+	}
+	return out, out.Convert(v.Type()).Equal(v)
+}
+
+// deepCopy returns a copy of v that shares no slices, maps or pointers with
+// it. Unexported fields are copied shallowly.
+func deepCopy[T any](v T) T {
+	var dst T
+	copyValue(reflect.ValueOf(&dst).Elem(), reflect.ValueOf(&v).Elem())
+	return dst
+}
+
+// copyValue deep copies src into the settable value dst of the same type.
+func copyValue(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.New(src.Type().Elem()))
+		copyValue(dst.Elem(), src.Elem())
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		elem := reflect.New(src.Elem().Type()).Elem()
+		copyValue(elem, src.Elem())
+		dst.Set(elem)
+	case reflect.Struct:
+		dst.Set(src)
+		for i := 0; i < src.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				copyValue(dst.Field(i), src.Field(i))
+			}
+		}
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.MakeSlice(src.Type(), src.Len(), src.Len()))
+		for i := 0; i < src.Len(); i++ {
+			copyValue(dst.Index(i), src.Index(i))
+		}
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			copyValue(dst.Index(i), src.Index(i))
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+		iter := src.MapRange()
+		for iter.Next() {
+			elem := reflect.New(src.Type().Elem()).Elem()
+			copyValue(elem, iter.Value())
+			dst.SetMapIndex(iter.Key(), elem)
+		}
+	default:
+		dst.Set(src)
+	}
+}
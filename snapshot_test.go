@@ -0,0 +1,102 @@
+// Package gathuk
+package gathuk
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+type SnapshotConfig struct {
+	Port     uint16
+	Database Database `config:"db"`
+	Hosts    []string
+	Labels   map[string]string
+	Cache    *Item
+}
+
+func TestGathukSnapshot(t *testing.T) {
+	t.Run("Test 1: restore after Set", func(t *testing.T) {
+		gt := NewGathuk[SnapshotConfig]()
+		customtests.OK(t, gt.LoadConfig(strings.NewReader(`{"port": 80, "db": {"user": "admin"}, "hosts": ["a"], "labels": {"env": "prod"}}`), "json"))
+		original := gt.GetConfig()
+
+		snap := gt.Snapshot()
+		customtests.OK(t, gt.Set("Port", 8080))
+		customtests.OK(t, gt.Set("Database.User", "guest"))
+		customtests.OK(t, gt.Set("Cache.Name", "redis"))
+		customtests.Equals(t, uint16(8080), gt.GetConfig().Port)
+		customtests.Equals(t, "guest", gt.GetConfig().Database.User)
+		customtests.Equals(t, "redis", gt.GetConfig().Cache.Name)
+
+		gt.Restore(snap)
+		customtests.Equals(t, original, gt.GetConfig())
+	})
+
+	t.Run("Test 2: snapshot is a deep copy", func(t *testing.T) {
+		gt := NewGathuk[SnapshotConfig]()
+		customtests.OK(t, gt.LoadConfig(strings.NewReader(`{"hosts": ["a"], "labels": {"env": "prod"}}`), "json"))
+
+		snap := gt.Snapshot()
+		cfg := gt.GetConfig()
+		cfg.Hosts[0] = "changed"
+		cfg.Labels["env"] = "dev"
+
+		customtests.Equals(t, []string{"a"}, snap.Hosts)
+		customtests.Equals(t, map[string]string{"env": "prod"}, snap.Labels)
+	})
+
+	t.Run("Test 3: Set errors", func(t *testing.T) {
+		gt := NewGathuk[SnapshotConfig]()
+
+		err := gt.Set("Missing", 1)
+		customtests.Assert(t, err != nil, "expected error for unknown field")
+
+		err = gt.Set("Port", "8080")
+		customtests.Assert(t, errors.Is(err, ErrTypeConversion), "expected ErrTypeConversion, got %v", err)
+	})
+
+	t.Run("Test 4: Set rejects lossy numbers", func(t *testing.T) {
+		gt := NewGathuk[SnapshotConfig]()
+		customtests.OK(t, gt.Set("Port", 8080.0))
+		customtests.Equals(t, uint16(8080), gt.GetConfig().Port)
+
+		for _, value := range []any{70000, -1, 1.5, uint64(1 << 63)} {
+			err := gt.Set("Port", value)
+			customtests.Assert(t, errors.Is(err, ErrTypeConversion), "expected ErrTypeConversion for %v, got %v", value, err)
+		}
+		customtests.Equals(t, uint16(8080), gt.GetConfig().Port)
+
+		err := gt.Set("Cache.ID", 1.5)
+		customtests.Assert(t, errors.Is(err, ErrTypeConversion), "expected ErrTypeConversion, got %v", err)
+		customtests.Assert(t, gt.GetConfig().Cache == nil, "a failed Set should not allocate Cache")
+	})
+}
+
+func TestGathukHas(t *testing.T) {
+	gt := NewGathuk[SnapshotConfig]()
+	customtests.OK(t, gt.LoadConfig(strings.NewReader(`{"port": 80, "db": {"user": "admin"}}`), "json"))
+
+	t.Run("Test 1: set paths", func(t *testing.T) {
+		customtests.Assert(t, gt.Has("Port"), "Port should be set")
+		customtests.Assert(t, gt.Has("db.user"), "db.user should be set")
+		customtests.Assert(t, gt.Has("Database.User"), "Database.User should be set")
+		customtests.Assert(t, gt.Has("db"), "db should be set through its user")
+	})
+
+	t.Run("Test 2: unset paths", func(t *testing.T) {
+		customtests.Assert(t, !gt.Has("db.server_port"), "db.server_port should not be set")
+		customtests.Assert(t, !gt.Has("Hosts"), "Hosts should not be set")
+		// behind a nil pointer
+		customtests.Assert(t, !gt.Has("Cache.Name"), "Cache.Name should not be set")
+		customtests.Assert(t, !gt.Has("cache"), "cache should not be set")
+		customtests.Assert(t, !gt.Has("missing"), "unknown path should not be set")
+	})
+
+	t.Run("Test 3: set later with Set", func(t *testing.T) {
+		customtests.OK(t, gt.Set("Cache.Name", "redis"))
+		customtests.Assert(t, gt.Has("Cache.Name"), "Cache.Name should be set")
+	})
+}
@@ -0,0 +1,131 @@
+// Package gathuk
+package gathuk
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ahyalfan/gathuk/internal/encoding/json"
+	"github.com/ahyalfan/gathuk/shared"
+)
+
+// WriteTemplate writes an example configuration listing every key T expects,
+// such as a .env.example for onboarding.
+//
+// The template is built from the type T alone, so no configuration needs to
+// be loaded. Each key gets the value of its `default` tag as placeholder, or
+// an empty value when it has none. In the "env" format every key is preceded
+// by a comment with its Go type, whether it is required and its `doc` tag.
+// The "json" format, which has no comments, writes the keys as nested objects
+// with zero values of the field types as placeholders.
+//
+// Parameters:
+//   - out: io.Writer to write the template to
+//   - format: The output format ("env" or "json")
+//
+// Returns an error if the format has no template support or writing fails.
+//
+// Example:
+//
+//	type Config struct {
+//	    Port     int `config:"port" doc:"HTTP listen port" default:"8080"`
+//	    Database struct {
+//	        Host string `required:"true"`
+//	    } `config:"db"`
+//	}
+//
+//	err := gathuk.NewGathuk[Config]().WriteTemplate(os.Stdout, "env")
+//	// # int - HTTP listen port
+//	// PORT=8080
+//	// # string, required
+//	// DB_HOST=
+func (g *Gathuk[T]) WriteTemplate(out io.Writer, format string) error {
+	fields := typeFields(reflect.TypeOf(&g.value).Elem())
+
+	var bys []byte
+	switch strings.ToLower(format) {
+	case "env":
+		bys = envTemplate(fields)
+	case "json":
+		var err error
+		bys, err = jsonTemplate(fields)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("write template: unsupported format %q: %w", format, ErrFormatNotSupported)
+	}
+
+	_, err := out.Write(bys)
+	return err
+}
+
+// envTemplate renders fields as commented KEY=placeholder lines.
+func envTemplate(fields []fieldInfo) []byte {
+	var build strings.Builder
+	for _, f := range fields {
+		if f.EnvKey == "" {
+			continue
+		}
+
+		build.WriteString("# ")
+		build.WriteString(f.Field.Type.String())
+		if required, _ := strconv.ParseBool(f.Field.Tag.Get("required")); required {
+			build.WriteString(", required")
+		}
+		if doc := f.Field.Tag.Get("doc"); doc != "" {
+			build.WriteString(" - ")
+			build.WriteString(doc)
+		}
+		build.WriteByte('\n')
+
+		build.WriteString(strings.ToUpper(f.EnvKey))
+		build.WriteByte('=')
+		build.WriteString(f.Field.Tag.Get("default"))
+		build.WriteByte('\n')
+	}
+	return []byte(build.String())
+}
+
+// jsonTemplate renders fields as a JSON document keeping declaration order.
+func jsonTemplate(fields []fieldInfo) ([]byte, error) {
+	var root shared.OrderedMap
+	for _, f := range fields {
+		if f.JSONKey == "" {
+			continue
+		}
+		setOrderedPath(&root, strings.Split(f.JSONKey, "."), placeholder(f.Field))
+	}
+
+	cdc := json.Codec[shared.OrderedMap]{}
+	return cdc.Encode(root)
+}
+
+// placeholder returns the template value of a field: its `default` tag, or
+// the zero value of its type.
+func placeholder(field reflect.StructField) any {
+	if def, ok := field.Tag.Lookup("default"); ok {
+		return def
+	}
+	if shared.IsScalarStruct(field.Type) {
+		return ""
+	}
+	return reflect.Zero(field.Type).Interface()
+}
+
+// setOrderedPath stores value under a dotted key path, creating nested
+// OrderedMaps as needed.
+func setOrderedPath(m *shared.OrderedMap, keys []string, value any) {
+	if len(keys) == 1 {
+		m.Set(keys[0], value)
+		return
+	}
+
+	existing, _ := m.Get(keys[0])
+	child, _ := existing.(shared.OrderedMap)
+	setOrderedPath(&child, keys[1:], value)
+	m.Set(keys[0], child)
+}
@@ -0,0 +1,57 @@
+// Package gathuk
+package gathuk
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+func TestWriteTemplate(t *testing.T) {
+	t.Run("Test 1: env template lists every key", func(t *testing.T) {
+		gt := NewGathuk[DocumentedConfig]()
+
+		var buf bytes.Buffer
+		err := gt.WriteTemplate(&buf, "env")
+		customtests.OK(t, err)
+		customtests.Equals(t, "# string - Service name\n"+
+			"NAME=\n"+
+			"# string, required - Database host\n"+
+			"DB_HOST=\n"+
+			"# int - Database port\n"+
+			"DB_PORT=5432\n", buf.String())
+	})
+
+	t.Run("Test 2: env template decodes back", func(t *testing.T) {
+		gt := NewGathuk[DocumentedConfig]()
+
+		var buf bytes.Buffer
+		customtests.OK(t, gt.WriteTemplate(&buf, "env"))
+		buf.WriteString("DB_HOST=localhost\n")
+		customtests.OK(t, gt.LoadConfig(&buf, "env"))
+		customtests.Equals(t, 5432, gt.GetConfig().Database.Port)
+	})
+
+	t.Run("Test 3: json template", func(t *testing.T) {
+		gt := NewGathuk[DocumentedConfig]()
+
+		var buf bytes.Buffer
+		err := gt.WriteTemplate(&buf, "json")
+		customtests.OK(t, err)
+
+		got := buf.String()
+		for _, key := range []string{`"name": ""`, `"db": {`, `"host": ""`, `"port": "5432"`} {
+			customtests.Assert(t, strings.Contains(got, key), "missing %s in %s", key, got)
+		}
+		customtests.Assert(t, strings.Index(got, `"name"`) < strings.Index(got, `"db"`), "keys out of order in %s", got)
+	})
+
+	t.Run("Test 4: unsupported format", func(t *testing.T) {
+		gt := NewGathuk[DocumentedConfig]()
+
+		err := gt.WriteTemplate(&bytes.Buffer{}, "yaml")
+		customtests.Assert(t, err != nil, "expected unsupported format error")
+	})
+}
@@ -0,0 +1,57 @@
+// Package gathuk
+package gathuk
+
+import (
+	"fmt"
+	"slices"
+)
+
+// AddTransform registers a transform run on the configuration after every
+// load, e.g. to normalize values, derive fields from others or apply checks
+// that tags cannot express.
+//
+// Transforms run in registration order once a load call has decoded and
+// merged its sources, before the validation tags are checked. They run on
+// the whole configuration after every call (LoadConfigFiles, LoadConfig,
+// Reload, DecodeBytes, ...), so a value transformed by an earlier call is
+// passed in again and transforms should be idempotent. The first error stops
+// the remaining transforms and is returned by the load call; a failed Reload
+// keeps the previous configuration.
+//
+// Parameters:
+//   - fn: The transform; it may modify the configuration in place
+//
+// Example:
+//
+//	gt.AddTransform(func(cfg *Config) error {
+//	    cfg.Host = strings.ToLower(cfg.Host)
+//	    return nil
+//	})
+//	gt.AddTransform(func(cfg *Config) error {
+//	    if cfg.TLS && cfg.CertFile == "" {
+//	        return errors.New("tls needs a cert_file")
+//	    }
+//	    return nil
+//	})
+func (g *Gathuk[T]) AddTransform(fn func(*T) error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.transforms = append(g.transforms, fn)
+}
+
+// afterLoad runs the registered transforms on a freshly loaded value, then
+// validates it.
+//
+// Returns the first transform or validation error.
+func (g *Gathuk[T]) afterLoad(val *T) error {
+	g.mu.RLock()
+	transforms := slices.Clone(g.transforms)
+	g.mu.RUnlock()
+
+	for i, fn := range transforms {
+		if err := fn(val); err != nil {
+			return fmt.Errorf("transform %d: %w", i+1, err)
+		}
+	}
+	return g.validate(val)
+}
@@ -0,0 +1,57 @@
+// Package gathuk
+package gathuk
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+func TestGathukAddTransform(t *testing.T) {
+	t.Run("Test 1: transforms run in order", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		var order []int
+		gt.AddTransform(func(s *Simple) error {
+			order = append(order, 1)
+			s.SimpleC = strings.ToUpper(s.SimpleC)
+			return nil
+		})
+		gt.AddTransform(func(s *Simple) error {
+			order = append(order, 2)
+			s.SimpleE = len(s.SimpleC)
+			return nil
+		})
+
+		err := gt.LoadConfigString("SIMPLE_C=hello", "env")
+		customtests.OK(t, err)
+		customtests.Equals(t, []int{1, 2}, order)
+		customtests.Equals(t, Simple{SimpleC: "HELLO", SimpleE: 5}, gt.GetConfig())
+	})
+
+	t.Run("Test 2: an error aborts the load", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		errTooShort := errors.New("simple_c too short")
+		ran := false
+		gt.AddTransform(func(s *Simple) error {
+			if len(s.SimpleC) < 3 {
+				return errTooShort
+			}
+			return nil
+		})
+		gt.AddTransform(func(s *Simple) error {
+			ran = true
+			return nil
+		})
+
+		err := gt.LoadConfigString("SIMPLE_C=hi", "env")
+		customtests.Assert(t, errors.Is(err, errTooShort), "expected transform error, got %v", err)
+		customtests.Assert(t, !ran, "transform after the failing one ran")
+
+		_, err = gt.DecodeBytes([]byte("SIMPLE_C=ok"), "env")
+		customtests.Assert(t, errors.Is(err, errTooShort), "expected transform error from DecodeBytes, got %v", err)
+	})
+}
@@ -1,29 +1,206 @@
 // Package gathuk
 package gathuk
 
-import "reflect"
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
 
-// resolveFilenames accepts a list of filenames and returns them as-is.
-// If no filenames are provided, it returns a slice containing ".env" as the fallback.
+// readAll reads src until EOF while honoring ctx.
 //
-// This function ensures that there is always at least one configuration file to load,
-// defaulting to the common ".env" file name if none is specified.
+// The read runs in a separate goroutine so a reader that blocks (e.g. a slow
+// network connection) does not delay cancellation: when ctx is done first,
+// readAll returns ctx.Err() immediately and the goroutine finishes in the
+// background once the reader returns. Contexts that can never be cancelled
+// are read inline.
 //
 // Parameters:
-//   - filenames: Variable number of file paths
+//   - ctx: Context controlling cancellation
+//   - src: The reader to consume
 //
-// Returns a slice of filenames (or [".env"] if empty).
+// Returns the data read, or ctx.Err() / the read error.
+func readAll(ctx context.Context, src io.Reader) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if ctx.Done() == nil {
+		_, err := io.Copy(&buf, src)
+		return buf.Bytes(), err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(&buf, src)
+		done <- err
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case err := <-done:
+		return buf.Bytes(), err
+	}
+}
+
+// resolveFilenames accepts a list of filenames and expands any glob patterns
+// among them. If no filenames are provided, it returns a slice containing ".env"
+// as the fallback.
+//
+// Plain filenames are returned as-is. Filenames containing glob metacharacters
+// (*, ?, [) are expanded with filepath.Glob and their matches are appended in
+// lexical order, so the precedence between matched files is predictable.
+// A pattern that matches nothing is treated as an error.
+//
+// Parameters:
+//   - filenames: Variable number of file paths or glob patterns
+//
+// Returns a slice of filenames (or [".env"] if empty) and any error encountered
+// while expanding patterns.
 //
 // Example:
 //
-//	files := resolveFilenames()                    // Returns: [".env"]
-//	files := resolveFilenames("config.env")        // Returns: ["config.env"]
-//	files := resolveFilenames("a.env", "b.env")    // Returns: ["a.env", "b.env"]
-func resolveFilenames(filenames ...string) []string {
+//	files, _ := resolveFilenames()                    // Returns: [".env"]
+//	files, _ := resolveFilenames("config.env")        // Returns: ["config.env"]
+//	files, _ := resolveFilenames("a.env", "b.env")    // Returns: ["a.env", "b.env"]
+//	files, _ := resolveFilenames("conf.d/*.env")      // Returns: ["conf.d/10-a.env", "conf.d/20-b.env"]
+func resolveFilenames(filenames ...string) ([]string, error) {
 	if len(filenames) == 0 {
-		return []string{".env"}
+		return []string{".env"}, nil
+	}
+
+	resolved := make([]string, 0, len(filenames))
+	for _, filename := range filenames {
+		if !hasGlobMeta(filename) {
+			resolved = append(resolved, filename)
+			continue
+		}
+
+		matches, err := filepath.Glob(filename)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config file pattern %q: %w", filename, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no config files match pattern %q: %w", filename, ErrFileNotFound)
+		}
+
+		sort.Strings(matches)
+		resolved = append(resolved, matches...)
+	}
+	return resolved, nil
+}
+
+// existingFiles returns the file paths and glob patterns of filenames that
+// refer to at least one existing file, keeping their order.
+//
+// Example:
+//
+//	existingFiles([]string{"base.env", "missing.env", "conf.d/*.env"})
+//	// Returns: ["base.env", "conf.d/*.env"] if conf.d has .env files
+func existingFiles(filenames []string) []string {
+	existing := make([]string, 0, len(filenames))
+	for _, filename := range filenames {
+		if hasGlobMeta(filename) {
+			if matches, _ := filepath.Glob(filename); len(matches) == 0 {
+				continue
+			}
+		} else if _, err := os.Stat(filename); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		existing = append(existing, filename)
 	}
-	return filenames
+	return existing
+}
+
+// hasGlobMeta reports whether path contains any of the glob metacharacters
+// recognized by filepath.Match.
+func hasGlobMeta(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// prefixFragment rewrites configuration data so that all of its keys are
+// nested under prefix.
+//
+// For "env" data every key line is prefixed with the uppercased prefix, where
+// dots become underscores (e.g. "services.api" → "SERVICES_API_"). Comment and
+// blank lines are kept as-is. For "json" data the document is wrapped into
+// nested objects, one per prefix segment.
+//
+// Parameters:
+//   - data: The fragment content
+//   - format: The fragment format ("env" or "json")
+//   - prefix: Dotted path of the subtree; an empty prefix leaves data unchanged
+//
+// Returns the rewritten data, or an error for formats without fragment support.
+//
+// Example:
+//
+//	prefixFragment([]byte("HOST=a"), "env", "api")          // Returns: "API_HOST=a"
+//	prefixFragment([]byte(`{"host":"a"}`), "json", "api")   // Returns: `{"api":{"host":"a"}}`
+func prefixFragment(data []byte, format, prefix string) ([]byte, error) {
+	if prefix == "" {
+		return data, nil
+	}
+
+	switch strings.ToLower(format) {
+	case "env":
+		keyPrefix := strings.ToUpper(strings.ReplaceAll(prefix, ".", "_")) + "_"
+
+		lines := bytes.Split(data, []byte{'\n'})
+		for i, line := range lines {
+			trimmed := bytes.TrimLeft(line, " \t")
+			indent := line[:len(line)-len(trimmed)]
+
+			export := []byte{}
+			if rest, ok := bytes.CutPrefix(trimmed, []byte("export")); ok && len(rest) > 0 && (rest[0] == ' ' || rest[0] == '\t') {
+				key := bytes.TrimLeft(rest, " \t")
+				export = trimmed[:len(trimmed)-len(key)]
+				trimmed = key
+			}
+
+			if len(trimmed) == 0 || !isKeyStart(trimmed[0]) || bytes.IndexByte(trimmed, '=') == -1 {
+				continue
+			}
+
+			rewritten := make([]byte, 0, len(line)+len(keyPrefix))
+			rewritten = append(rewritten, indent...)
+			rewritten = append(rewritten, export...)
+			rewritten = append(rewritten, keyPrefix...)
+			rewritten = append(rewritten, trimmed...)
+			lines[i] = rewritten
+		}
+		return bytes.Join(lines, []byte{'\n'}), nil
+	case "json":
+		segments := strings.Split(prefix, ".")
+
+		var out bytes.Buffer
+		for _, segment := range segments {
+			out.WriteString("{")
+			out.WriteString(strconv.Quote(segment))
+			out.WriteString(":")
+		}
+		out.Write(data)
+		out.WriteString(strings.Repeat("}", len(segments)))
+		return out.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("config fragments are not supported for format %q: %w", format, ErrFormatNotSupported)
+	}
+}
+
+// isKeyStart reports whether b can start a .env key.
+func isKeyStart(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
 }
 
 // isZeroValue checks if a reflect.Value represents a zero value or nil.
@@ -0,0 +1,202 @@
+// Package gathuk
+package gathuk
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ahyalfan/gathuk/internal/encoding/dotenv"
+	"github.com/ahyalfan/gathuk/internal/encoding/json"
+	"github.com/ahyalfan/gathuk/shared"
+)
+
+// validate checks a loaded configuration against the validation tags
+// declared on the fields of T.
+//
+// Supported tags:
+//   - `required:"true"`: the field must not hold its zero value. The error
+//     wraps ErrRequiredMissing.
+//   - `oneof:"80 443 8080"`: the field value must be one of the space separated
+//     values. Works for string, integer, unsigned integer and float fields.
+//     Zero values are not checked, so unset optional fields are accepted.
+//
+// Nested structs and non-nil pointers to structs are validated recursively.
+// Non-struct configurations (maps, slices, any) are not validated.
+//
+// Returns the first validation error found, naming the field path
+// (e.g. "Server.Port") and the allowed values.
+func (g *Gathuk[T]) validate(val *T) error {
+	v := reflect.ValueOf(val).Elem()
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	return validateStruct(v, "")
+}
+
+// validateStruct validates each field of a struct value, recursing into
+// nested structs and non-nil pointers to structs.
+//
+// Parameters:
+//   - v: The struct value to validate
+//   - path: The dotted path of v from the configuration root
+//
+// Returns the first validation error found.
+func validateStruct(v reflect.Value, path string) error {
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		structField := t.Field(i)
+		field := v.Field(i)
+
+		// fields of embedded structs are promoted, so they keep the parent path
+		if structField.Anonymous && field.Kind() == reflect.Struct {
+			if err := validateStruct(field, path); err != nil {
+				return err
+			}
+			continue
+		}
+		if !structField.IsExported() {
+			continue
+		}
+
+		fieldPath := structField.Name
+		if path != "" {
+			fieldPath = path + "." + structField.Name
+		}
+
+		if required, _ := strconv.ParseBool(structField.Tag.Get("required")); required && field.IsZero() {
+			return fmt.Errorf("validation error at %s: %w", fieldPath, ErrRequiredMissing)
+		}
+
+		// a nil pointer has nothing to validate; a set one is checked like
+		// the struct it points to
+		if field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.Struct &&
+			!shared.IsScalarStruct(field.Type().Elem()) {
+			if field.IsNil() {
+				continue
+			}
+			field = field.Elem()
+		}
+
+		if field.Kind() == reflect.Struct && !shared.IsScalarStruct(field.Type()) {
+			if err := validateStruct(field, fieldPath); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if oneof, ok := structField.Tag.Lookup("oneof"); ok {
+			if err := validateOneOf(field, fieldPath, strings.Fields(oneof)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateOneOf checks that a field holds one of the allowed values.
+//
+// The allowed values are parsed into the kind of the field before comparing,
+// so `oneof:"80 443"` matches an int field holding 443 and a float field
+// holding 443.0 alike. Zero values are accepted.
+//
+// Parameters:
+//   - field: The field value to check
+//   - path: The dotted path of the field, used in the error message
+//   - allowed: The allowed values as written in the tag
+//
+// Returns an error if the value is not allowed or the tag cannot be applied
+// to the field type.
+func validateOneOf(field reflect.Value, path string, allowed []string) error {
+	if field.IsZero() {
+		return nil
+	}
+
+	for _, candidate := range allowed {
+		var match bool
+		switch field.Kind() {
+		case reflect.String:
+			match = field.String() == candidate
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i, err := strconv.ParseInt(candidate, 0, 64)
+			match = err == nil && field.Int() == i
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			u, err := strconv.ParseUint(candidate, 0, 64)
+			match = err == nil && field.Uint() == u
+		case reflect.Float32, reflect.Float64:
+			f, err := strconv.ParseFloat(candidate, 64)
+			match = err == nil && field.Float() == f
+		default:
+			return fmt.Errorf("validation error at %s: oneof is not supported for %s", path, field.Type())
+		}
+		if match {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("validation error at %s: value %v is not one of [%s]", path, field.Interface(), strings.Join(allowed, " "))
+}
+
+// CheckKeyCollisions reports configuration keys that more than one field of T
+// maps to.
+//
+// Nested prefixes are joined with "_" in .env files, so differently nested
+// fields can end up with the same key, e.g. a field `DB struct{ Host string }`
+// and a field `DBHost string` both produce DB_HOST. Their values then silently
+// clobber each other. Calling CheckKeyCollisions at startup (or in a test)
+// catches such mistakes early. Both the .env and the JSON keys are checked.
+//
+// Returns one message per colliding key naming the key and the Go paths of
+// the fields producing it, or nil if every key is unique.
+//
+// Example:
+//
+//	type Config struct {
+//	    DB     struct{ Host string } `config:"db"`
+//	    DBHost string                `config:"db_host"`
+//	}
+//
+//	gathuk.NewGathuk[Config]().CheckKeyCollisions()
+//	// [env key "DB_HOST" is generated by DB.Host, DBHost]
+func (g *Gathuk[T]) CheckKeyCollisions() []string {
+	fields := typeFields(reflect.TypeOf(&g.value).Elem())
+
+	envKeys := make([]string, len(fields))
+	jsonKeys := make([]string, len(fields))
+	for i, f := range fields {
+		if dotenv.FieldKey(f.Field) != "" {
+			envKeys[i] = strings.ToUpper(f.EnvKey)
+		}
+		if json.FieldKey(f.Field) != "" {
+			jsonKeys[i] = f.JSONKey
+		}
+	}
+
+	collisions := keyCollisions("env", envKeys, fields)
+	return append(collisions, keyCollisions("json", jsonKeys, fields)...)
+}
+
+// keyCollisions returns a message for every key shared by several fields.
+// keys[i] is the key of fields[i]; empty keys are ignored.
+func keyCollisions(format string, keys []string, fields []fieldInfo) []string {
+	paths := make(map[string][]string)
+	var order []string
+	for i, key := range keys {
+		if key == "" {
+			continue
+		}
+		if _, ok := paths[key]; !ok {
+			order = append(order, key)
+		}
+		paths[key] = append(paths[key], fields[i].Path)
+	}
+
+	var collisions []string
+	for _, key := range order {
+		if len(paths[key]) > 1 {
+			collisions = append(collisions, fmt.Sprintf("%s key %q is generated by %s", format, key, strings.Join(paths[key], ", ")))
+		}
+	}
+	return collisions
+}
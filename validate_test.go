@@ -0,0 +1,87 @@
+// Package gathuk
+package gathuk
+
+import (
+	"strings"
+	"testing"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+type Listener struct {
+	Port  int     `oneof:"80 443 8080"`
+	Ratio float64 `oneof:"0.5 1"`
+}
+
+type ListenerConfig struct {
+	Name     string   `oneof:"public internal"`
+	Listener Listener `config:"listener"`
+}
+
+func TestValidateOneOf(t *testing.T) {
+	t.Run("Test 1: allowed numeric value", func(t *testing.T) {
+		gt := NewGathuk[ListenerConfig]()
+
+		err := gt.LoadConfig(strings.NewReader("NAME=public\nLISTENER_PORT=443\nLISTENER_RATIO=0.5"), "env")
+		customtests.OK(t, err)
+		customtests.Equals(t, 443, gt.GetConfig().Listener.Port)
+	})
+
+	t.Run("Test 2: disallowed numeric value", func(t *testing.T) {
+		gt := NewGathuk[ListenerConfig]()
+
+		err := gt.LoadConfig(strings.NewReader(`{"listener": {"port": 8081}}`), "json")
+		customtests.Assert(t, err != nil, "expected oneof validation error")
+		customtests.Assert(t, strings.Contains(err.Error(), "Listener.Port"), "error does not name the field: %v", err)
+		customtests.Assert(t, strings.Contains(err.Error(), "[80 443 8080]"), "error does not list allowed values: %v", err)
+	})
+
+	t.Run("Test 3: unset values are not checked", func(t *testing.T) {
+		gt := NewGathuk[ListenerConfig]()
+
+		err := gt.LoadConfig(strings.NewReader("NAME=internal"), "env")
+		customtests.OK(t, err)
+	})
+
+	t.Run("Test 4: fields behind a pointer are checked", func(t *testing.T) {
+		type Config struct {
+			Listener *Listener `config:"listener"`
+		}
+		gt := NewGathuk[Config]()
+
+		// a nil pointer has nothing to check
+		customtests.OK(t, gt.LoadConfig(strings.NewReader(`{}`), "json"))
+
+		err := gt.LoadConfig(strings.NewReader(`{"listener": {"port": 8081}}`), "json")
+		customtests.Assert(t, err != nil, "expected oneof validation error")
+		customtests.Assert(t, strings.Contains(err.Error(), "Listener.Port"), "error does not name the field: %v", err)
+
+		customtests.OK(t, gt.LoadConfig(strings.NewReader(`{"listener": {"port": 443}}`), "json"))
+		customtests.Equals(t, 443, gt.GetConfig().Listener.Port)
+	})
+}
+
+type CollidingDB struct {
+	Host string
+	Port int
+}
+
+type CollidingConfig struct {
+	DB     CollidingDB `config:"db"`
+	DBHost string      `config:"db_host"`
+	Name   string
+}
+
+func TestCheckKeyCollisions(t *testing.T) {
+	t.Run("Test 1: nested prefix collides with a flat field", func(t *testing.T) {
+		gt := NewGathuk[CollidingConfig]()
+
+		customtests.Equals(t, []string{`env key "DB_HOST" is generated by DB.Host, DBHost`}, gt.CheckKeyCollisions())
+	})
+
+	t.Run("Test 2: unique keys", func(t *testing.T) {
+		gt := NewGathuk[ListenerConfig]()
+
+		customtests.Equals(t, 0, len(gt.CheckKeyCollisions()))
+	})
+}
@@ -0,0 +1,229 @@
+// Package gathuk
+package gathuk
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"os"
+	"slices"
+	"time"
+)
+
+// defaultWatchInterval is the polling interval used when WatchInterval is zero.
+const defaultWatchInterval = time.Second
+
+// fileState is the part of a file's metadata used to detect changes.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// trackFile records a loaded config file so it can be watched and reloaded.
+func (g *Gathuk[T]) trackFile(filename string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !slices.Contains(g.files, filename) {
+		g.files = append(g.files, filename)
+	}
+}
+
+// WatchChan watches the config files loaded so far and delivers the new
+// configuration on the returned channel every time one of them changes.
+//
+// Files are polled every WatchInterval (one second by default). On a change,
+// all files are reloaded in their original order into a fresh value, which is
+// validated and then swapped in, so GetConfig returns the new configuration,
+// and the OnChange callbacks are run. Reloads that fail (e.g. a file is half
+// written) are logged and skipped; the previous configuration stays in place.
+// Like Reload, a reload only reads the files, so values set from other
+// sources are dropped.
+//
+// The channel is closed when ctx is cancelled. If the consumer is slow, an
+// undelivered update is replaced by the next one, so the consumer always
+// receives the latest configuration.
+//
+// Parameters:
+//   - ctx: Context that stops watching when cancelled
+//
+// Returns the update channel, or an error if no config file has been loaded
+// yet with LoadConfigFiles or LoadConfigDir.
+//
+// Example:
+//
+//	err := gt.LoadConfigFiles("config.env")
+//	updates, err := gt.WatchChan(ctx)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	for {
+//	    select {
+//	    case cfg, ok := <-updates:
+//	        if !ok {
+//	            return nil
+//	        }
+//	        applyConfig(cfg)
+//	    case <-ctx.Done():
+//	        return ctx.Err()
+//	    }
+//	}
+func (g *Gathuk[T]) WatchChan(ctx context.Context) (<-chan T, error) {
+	files := g.trackedFiles()
+	if len(files) == 0 {
+		return nil, errors.New("no config files loaded to watch")
+	}
+
+	interval := g.WatchInterval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	updates := make(chan T, 1)
+	states := statFiles(files)
+
+	go func() {
+		defer close(updates)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			current := statFiles(files)
+			if maps.Equal(states, current) {
+				continue
+			}
+			states = current
+
+			next, err := g.reload(ctx, files)
+			if err != nil {
+				if ctx.Err() == nil {
+					g.logger.Error("config reload failed", "error", err)
+				}
+				continue
+			}
+
+			// drop an update the consumer has not received yet
+			select {
+			case <-updates:
+			default:
+			}
+			updates <- next
+		}
+	}()
+
+	return updates, nil
+}
+
+// Reload loads all config files loaded so far again, in their original
+// order, and replaces the current configuration with the result.
+//
+// The new configuration is built and validated completely in a fresh value
+// before it is swapped in with a single assignment, so concurrent GetConfig
+// callers see either the old or the new configuration, never a mix. If any
+// file fails to load or the result fails validation, the current
+// configuration is left intact. Callbacks registered with OnChange run after
+// a successful swap.
+//
+// Reload only reads the config files, on top of the `default` tags. Values
+// that came from other sources (LoadConfig, AppendConfig, LoadFromEnv,
+// BindFlags, Set, ...) are not part of the reloaded configuration; apply them
+// again afterwards, e.g. from an OnChange callback, if they must survive.
+//
+// Returns an error if no config file has been loaded yet, or reloading fails.
+//
+// Example:
+//
+//	// on SIGHUP
+//	if err := gt.Reload(); err != nil {
+//	    log.Printf("keeping previous config: %v", err)
+//	}
+func (g *Gathuk[T]) Reload() error {
+	files := g.trackedFiles()
+	if len(files) == 0 {
+		return errors.New("no config files loaded to reload")
+	}
+	_, err := g.reload(context.Background(), files)
+	return err
+}
+
+// trackedFiles returns a copy of the config files loaded so far.
+func (g *Gathuk[T]) trackedFiles() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return slices.Clone(g.files)
+}
+
+// OnChange registers fn to be called after every successful Reload, and
+// every reload done by WatchChan, with the previous and the new
+// configuration. Callbacks run in registration order on the reloading
+// goroutine, after the new configuration has been swapped in.
+//
+// Parameters:
+//   - fn: The callback to run
+//
+// Example:
+//
+//	gt.OnChange(func(old, new Config) {
+//	    for _, d := range gathuk.Diff(old, new) {
+//	        log.Printf("%s: %v -> %v", d.Path, d.Old, d.New)
+//	    }
+//	})
+func (g *Gathuk[T]) OnChange(fn func(old, new T)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.onChange = append(g.onChange, fn)
+}
+
+// reload loads files into a fresh value built from the `default` tags alone,
+// validates it and swaps it in as the current configuration, then runs the
+// OnChange callbacks.
+//
+// Parameters:
+//   - ctx: Context controlling cancellation while reading
+//   - files: The config files to load, in order
+//
+// Returns the new configuration, or an error if loading or validation fails,
+// in which case the current configuration is left unchanged.
+func (g *Gathuk[T]) reload(ctx context.Context, files []string) (T, error) {
+	next := g.defaultValue()
+	for _, filename := range files {
+		if err := g.loadFile(ctx, filename, &next); err != nil {
+			return next, err
+		}
+	}
+	if err := g.afterLoad(&next); err != nil {
+		return next, err
+	}
+
+	g.mu.Lock()
+	old := g.value
+	g.value = next
+	callbacks := slices.Clone(g.onChange)
+	g.mu.Unlock()
+
+	for _, fn := range callbacks {
+		fn(old, next)
+	}
+	return next, nil
+}
+
+// statFiles returns the current state of each file. Files that cannot be
+// stat'ed get a zero state, so their reappearance is detected as a change.
+func statFiles(files []string) map[string]fileState {
+	states := make(map[string]fileState, len(files))
+	for _, filename := range files {
+		if info, err := os.Stat(filename); err == nil {
+			states[filename] = fileState{modTime: info.ModTime(), size: info.Size()}
+		} else {
+			states[filename] = fileState{}
+		}
+	}
+	return states
+}
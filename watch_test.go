@@ -0,0 +1,159 @@
+// Package gathuk
+package gathuk
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	customtests "github.com/ahyalfan/gathuk/internal/utils/custom-test"
+)
+
+func TestGathukWatchChan(t *testing.T) {
+	t.Run("Test 1: receive an update after modifying the file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "watch.env")
+		customtests.OK(t, os.WriteFile(file, []byte("SIMPLE_C=before\n"), 0o644))
+
+		gt := NewGathuk[Simple]()
+		gt.WatchInterval = 10 * time.Millisecond
+		customtests.OK(t, gt.LoadConfigFiles(file))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		updates, err := gt.WatchChan(ctx)
+		customtests.OK(t, err)
+
+		customtests.OK(t, os.WriteFile(file, []byte("SIMPLE_C=after\nSIMPLE_E=2\n"), 0o644))
+
+		select {
+		case cfg := <-updates:
+			customtests.Equals(t, Simple{SimpleC: "after", SimpleE: 2}, cfg)
+			customtests.Equals(t, cfg, gt.GetConfig())
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for config update")
+		}
+
+		cancel()
+		for range updates {
+		}
+	})
+
+	t.Run("Test 2: load while reloading", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "watch.env")
+		customtests.OK(t, os.WriteFile(file, []byte("SIMPLE_C=before\n"), 0o644))
+
+		gt := NewGathuk[Simple]()
+		gt.WatchInterval = time.Millisecond
+		customtests.OK(t, gt.LoadConfigFiles(file))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		updates, err := gt.WatchChan(ctx)
+		customtests.OK(t, err)
+
+		for i := range 50 {
+			customtests.OK(t, os.WriteFile(file, []byte(fmt.Sprintf("SIMPLE_C=v%d\n", i)), 0o644))
+			customtests.OK(t, gt.LoadConfigString(fmt.Sprintf("SIMPLE_E=%d", i), "env"))
+			time.Sleep(time.Millisecond)
+		}
+
+		cancel()
+		for range updates {
+		}
+	})
+
+	t.Run("Test 3: nothing to watch", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		_, err := gt.WatchChan(context.Background())
+		customtests.Assert(t, err != nil, "expected error when no files were loaded")
+	})
+}
+
+func TestGathukReload(t *testing.T) {
+	t.Run("Test 1: swap in the new config and notify", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "reload.env")
+		customtests.OK(t, os.WriteFile(file, []byte("SIMPLE_C=before\n"), 0o644))
+
+		gt := NewGathuk[Simple]()
+		customtests.OK(t, gt.LoadConfigFiles(file))
+
+		var calls [][2]Simple
+		gt.OnChange(func(old, new Simple) {
+			calls = append(calls, [2]Simple{old, new})
+		})
+
+		customtests.OK(t, os.WriteFile(file, []byte("SIMPLE_C=after\nSIMPLE_E=2\n"), 0o644))
+		customtests.OK(t, gt.Reload())
+
+		customtests.Equals(t, Simple{SimpleC: "after", SimpleE: 2}, gt.GetConfig())
+		customtests.Equals(t, [][2]Simple{{{SimpleC: "before"}, {SimpleC: "after", SimpleE: 2}}}, calls)
+	})
+
+	t.Run("Test 2: broken file keeps the previous config", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "reload.json")
+		customtests.OK(t, os.WriteFile(file, []byte(`{"simple_c": "good", "simple_e": 1}`), 0o644))
+
+		gt := NewGathuk[Simple]()
+		customtests.OK(t, gt.LoadConfigFiles(file))
+
+		called := false
+		gt.OnChange(func(old, new Simple) { called = true })
+
+		customtests.OK(t, os.WriteFile(file, []byte(`{"simple_c": "bad", "simple_e": `), 0o644))
+		err := gt.Reload()
+
+		customtests.Assert(t, err != nil, "expected reload of a broken file to fail")
+		customtests.Equals(t, Simple{SimpleC: "good", SimpleE: 1}, gt.GetConfig())
+		customtests.Assert(t, !called, "OnChange must not run after a failed reload")
+	})
+
+	t.Run("Test 3: nothing to reload", func(t *testing.T) {
+		gt := NewGathuk[Simple]()
+
+		err := gt.Reload()
+		customtests.Assert(t, err != nil, "expected error when no files were loaded")
+	})
+
+	t.Run("Test 4: values from other sources are not reloaded", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "reload.env")
+		customtests.OK(t, os.WriteFile(file, []byte("SIMPLE_C=file\n"), 0o644))
+
+		gt := NewGathuk[Simple]()
+		customtests.OK(t, gt.LoadConfigFiles(file))
+		customtests.OK(t, gt.LoadConfigString("SIMPLE_E=7", "env"))
+		customtests.Equals(t, Simple{SimpleC: "file", SimpleE: 7}, gt.GetConfig())
+
+		customtests.OK(t, gt.Reload())
+		customtests.Equals(t, Simple{SimpleC: "file"}, gt.GetConfig())
+	})
+
+	t.Run("Test 5: register transforms and load files while reloading", func(t *testing.T) {
+		dir := t.TempDir()
+		file := filepath.Join(dir, "reload.env")
+		customtests.OK(t, os.WriteFile(file, []byte("SIMPLE_C=file\n"), 0o644))
+
+		gt := NewGathuk[Simple]()
+		customtests.OK(t, gt.LoadConfigFiles(file))
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for range 50 {
+				_ = gt.Reload()
+			}
+		}()
+		for i := range 50 {
+			gt.AddTransform(func(*Simple) error { return nil })
+			other := filepath.Join(dir, fmt.Sprintf("other%d.env", i))
+			customtests.OK(t, os.WriteFile(other, []byte("SIMPLE_E=1\n"), 0o644))
+			customtests.OK(t, gt.LoadConfigFiles(other))
+		}
+		<-done
+	})
+}